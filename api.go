@@ -2,7 +2,7 @@ package shopify
 
 import (
 	"bytes"
-	"fmt"
+	"context"
 	"github.com/jpillora/backoff"
 	"io"
 	"net/http"
@@ -21,73 +21,209 @@ type API struct {
 	AccessToken string // permanent store access token
 	Token       string // API client token
 	Secret      string // API client secret for this shop
-	client      *http.Client
 
-	callLimit  int
-	callsMade  int
-	backoff    *backoff.Backoff
-	retryCount int
+	// Limiter throttles calls to Shop. When nil, the limiter shared by
+	// every API for the same shop is used.
+	Limiter *RateLimiter
+
+	// MaxRetries is the retry budget of a single call. Zero means
+	// MAX_RETRIES, a negative value disables retries.
+	MaxRetries int
+
+	// APIVersion pins calls to an Admin API version such as "2024-07",
+	// rewriting /admin/... endpoints to /admin/api/{version}/.... When empty
+	// the legacy unversioned paths are used.
+	APIVersion string
+
+	// OnVersionNotice, when set, is called for responses served by another
+	// version than APIVersion or flagged as deprecated by Shopify.
+	OnVersionNotice func(VersionNotice)
+
+	clientConfig
+}
+
+// NewAPI returns an API for shop authenticated with a permanent access token.
+func NewAPI(shop, accessToken string, opts ...ClientOption) *API {
+	api := &API{Shop: shop, AccessToken: accessToken}
+	api.clientConfig.apply(opts)
+	return api
 }
 
-type errorResponse struct {
-	Errors map[string]interface{} `json:"errors"`
+// Apply configures an existing API, e.g. one built as a struct literal.
+func (api *API) Apply(opts ...ClientOption) {
+	api.clientConfig.apply(opts)
 }
 
 func (api *API) request(endpoint string, method string, params map[string]interface{}, body io.Reader) (result *bytes.Buffer, status int, err error) {
-	if api.client == nil {
-		api.client = &http.Client{}
+	return api.requestContext(context.Background(), endpoint, method, params, body)
+}
+
+// requestContext performs the call bound to ctx; cancelling ctx aborts both
+// the HTTP round trip and any backoff sleep between retries.
+//
+// The body is buffered up front so it can be replayed on retry. A 429 is
+// always retried since Shopify did not process the call; 5xx responses and
+// connection errors are only retried for idempotent methods. Any final status
+// of 400 or above is returned as a *ResponseError along with the body.
+func (api *API) requestContext(ctx context.Context, endpoint string, method string, params map[string]interface{}, body io.Reader) (result *bytes.Buffer, status int, err error) {
+	result, status, _, err = api.requestHeader(ctx, endpoint, method, params, body)
+	return
+}
+
+// requestHeader is requestContext that also returns the response headers,
+// for callers that need e.g. the Link header.
+func (api *API) requestHeader(ctx context.Context, endpoint string, method string, params map[string]interface{}, body io.Reader) (result *bytes.Buffer, status int, header http.Header, err error) {
+	client := api.httpClient()
+	limiter := api.limiter()
+	b := &backoff.Backoff{
+		//These are the defaults
+		Min:    100 * time.Millisecond,
+		Max:    2 * time.Second,
+		Jitter: true,
 	}
-	if api.backoff == nil {
-		api.backoff = &backoff.Backoff{
-			//These are the defaults
-			Min:    100 * time.Millisecond,
-			Max:    2 * time.Second,
-			Jitter: true,
+
+	var payload []byte
+	if body != nil {
+		if payload, err = io.ReadAll(body); err != nil {
+			return
 		}
 	}
-	if api.callLimit == 0 {
-		api.callLimit = BUCKET_LIMIT
-	}
 
-	uri := fmt.Sprintf("https://%s%s", api.Shop, endpoint)
-	req, err := http.NewRequest(method, uri, body)
-	if err != nil {
+	endpoint = api.versioned(endpoint)
+	uri := api.shopURL(api.Shop) + endpoint
+
+	// GraphQL calls are throttled on query cost by GraphQL itself rather
+	// than on the REST call count.
+	rest := !isGraphQLEndpoint(endpoint)
+
+	for retries := 0; ; retries++ {
+		canRetry := retries < api.maxRetries()
+
+		if rest {
+			if err = limiter.Wait(ctx); err != nil {
+				return
+			}
+		}
+
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, method, uri, bytes.NewReader(payload))
+		if err != nil {
+			return
+		}
+
+		if api.AccessToken != "" {
+			req.Header.Set("X-Shopify-Access-Token", api.AccessToken)
+		} else {
+			req.SetBasicAuth(api.Token, api.Secret)
+		}
+		req.Header.Add("Content-Type", "application/json")
+		api.setHeaders(req)
+
+		var resp *http.Response
+		resp, err = client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil || !canRetry || !isIdempotent(method) {
+				return
+			}
+			if err = sleepContext(ctx, b.Duration()); err != nil {
+				return
+			}
+			continue
+		}
+
+		limiter.Update(parseAPICallLimit(resp.Header.Get("X-Shopify-Shop-Api-Call-Limit")))
+		api.checkVersion(method, endpoint, resp.Header)
+
+		status = resp.StatusCode
+		header = resp.Header
+		retry := status == 429 || (status >= 500 && isIdempotent(method))
+		if retry && canRetry {
+			resp.Body.Close()
+
+			wait := b.Duration()
+			if status == 429 { // statusTooManyRequests
+				if rest {
+					limiter.Full()
+				}
+				if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+					wait = d
+				}
+			}
+			if err = sleepContext(ctx, wait); err != nil {
+				return
+			}
+			// try again
+			continue
+		}
+
+		result = &bytes.Buffer{}
+		_, err = io.Copy(result, resp.Body)
+		resp.Body.Close()
+		if err == nil && status >= 400 {
+			e := parseResponseError(status, result)
+			e.Method = method
+			e.Endpoint = endpoint
+			e.RequestID = resp.Header.Get("X-Request-Id")
+			err = e
+		}
 		return
 	}
+}
 
-	if api.AccessToken != "" {
-		req.Header.Set("X-Shopify-Access-Token", api.AccessToken)
-	} else {
-		req.SetBasicAuth(api.Token, api.Secret)
+func (api *API) maxRetries() int {
+	if api.MaxRetries == 0 {
+		return MAX_RETRIES
 	}
-	req.Header.Add("Content-Type", "application/json")
+	return api.MaxRetries
+}
 
-	resp, err := api.client.Do(req)
-	if err != nil {
-		return
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
 	}
+	return false
+}
 
-	calls, total := parseAPICallLimit(resp.Header.Get("HTTP_X_SHOPIFY_SHOP_API_CALL_LIMIT"))
-	api.callsMade = calls
-	api.callLimit = total
-
-	status = resp.StatusCode
-	if status == 429 { // statusTooManyRequests
-		if api.retryCount < MAX_RETRIES {
-			api.retryCount = api.retryCount + 1
-			b := api.backoff.Duration()
-			time.Sleep(b)
-			// try again
-			return api.request(endpoint, method, params, body)
+// parseRetryAfter reads a Retry-After header, given either in (possibly
+// fractional) seconds as Shopify sends it, or as an HTTP date.
+func parseRetryAfter(str string) (time.Duration, bool) {
+	if str == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseFloat(str, 64); err == nil {
+		if secs < 0 {
+			return 0, false
 		}
-		// else just return
+		return time.Duration(secs * float64(time.Second)), true
 	}
+	if t, err := http.ParseTime(str); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
 
-	result = &bytes.Buffer{}
-	if _, err = io.Copy(result, resp.Body); err != nil {
-		return
+func (api *API) limiter() *RateLimiter {
+	if api.Limiter != nil {
+		return api.Limiter
+	}
+	return limiterFor(api.Shop)
+}
+
+// sleepContext waits for d, returning early with ctx.Err() if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
 	}
-	return
 }
 
 func parseAPICallLimit(str string) (int, int) {
@@ -1,9 +1,21 @@
 package shopify
 
 import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -88,7 +100,7 @@ func TestListCreateGetDeleteProduct(t *testing.T) {
 	}
 
 	// List and delete all
-	_, err := api.Products()
+	_, err := api.Products(nil)
 	if err != nil {
 		fmt.Printf("Err fetching products: %v", err)
 	}
@@ -96,24 +108,24 @@ func TestListCreateGetDeleteProduct(t *testing.T) {
 	// create
 	newProduct := api.NewProduct()
 	newProduct.Title = "T-shirt"
-	newProduct.PublishedAt = time.Now()
+	newProduct.PublishedAt = NewTimestamp(time.Now())
 	newProduct.ProductType = "shirts"
-	err = newProduct.Save()
+	err = newProduct.Save(nil)
 	if err != nil {
 		t.Fatalf("Error saving product: %s", err)
 	}
-	if newProduct.Id == 0 {
+	if newProduct.ID == 0 {
 		t.Errorf("Missing ID for newly created product")
 	}
 
 	// get new product by id
-	product, err := api.Product(newProduct.Id)
+	product, err := api.Product(newProduct.ID)
 
 	if err != nil {
-		t.Errorf("Error fetching product (%v): %v", newProduct.Id, err)
+		t.Errorf("Error fetching product (%v): %v", newProduct.ID, err)
 	}
 
-	if product.Id != newProduct.Id {
+	if product.ID != newProduct.ID {
 		t.Errorf("Expected retrieved product to have the same ID as newly created product")
 	}
 
@@ -123,3 +135,1476 @@ func TestListCreateGetDeleteProduct(t *testing.T) {
 		t.Errorf("Error deleting product: %s", err)
 	}
 }
+
+// newTestAPI points an API at a local TLS server so requests never leave the host.
+func newTestAPI(handler http.HandlerFunc) (*API, *httptest.Server) {
+	srv := httptest.NewTLSServer(handler)
+	limiter := NewRateLimiter()
+	limiter.interval = time.Millisecond
+	a := NewAPI("test.myshopify.com", "token", WithHTTPClient(srv.Client()), WithBaseURL(srv.URL))
+	a.Limiter = limiter
+	return a, srv
+}
+
+func TestRequestContextCancelled(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := a.ProductsCtx(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRequestContextCancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int32
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		// Cancel once the client is waiting out the Retry-After.
+		time.AfterFunc(50*time.Millisecond, cancel)
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(429)
+	})
+	defer srv.Close()
+
+	start := time.Now()
+	_, err := a.ProductCtx(ctx, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected one request before the backoff, got %d", n)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the backoff to be cut short, waited %s", elapsed)
+	}
+}
+
+func TestRateLimiterSpacesCallsPastSlowdown(t *testing.T) {
+	l := NewRateLimiter()
+	l.interval = 10 * time.Millisecond
+	l.Update(BUCKET_SLOWDOWN, BUCKET_LIMIT)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(context.Background()); err != nil {
+				t.Errorf("Unexpected error waiting: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the last of 8 queued callers waits for 8 calls to leak out
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("Expected calls to be spaced out, all 8 went through in %v", elapsed)
+	}
+	if calls, limit := l.Status(); calls > limit {
+		t.Errorf("Bucket overflowed: %d/%d", calls, limit)
+	}
+}
+
+func TestRateLimiterSharedPerShop(t *testing.T) {
+	a := &API{Shop: "shared-limiter.myshopify.com"}
+	b := &API{Shop: "shared-limiter.myshopify.com"}
+	c := &API{Shop: "other-limiter.myshopify.com"}
+
+	if a.limiter() != b.limiter() {
+		t.Errorf("Expected APIs for the same shop to share a limiter")
+	}
+	if a.limiter() == c.limiter() {
+		t.Errorf("Expected APIs for different shops to have separate limiters")
+	}
+}
+
+func TestRateLimiterLeakRateFollowsLimit(t *testing.T) {
+	l := NewRateLimiter()
+	l.Update(10, 80)
+	if l.interval != 250*time.Millisecond {
+		t.Errorf("Expected an 80 call bucket to leak 4 calls per second, got one per %v", l.interval)
+	}
+	l.Update(10, BUCKET_LIMIT)
+	if l.interval != 500*time.Millisecond {
+		t.Errorf("Expected a %d call bucket to leak 2 calls per second, got one per %v", BUCKET_LIMIT, l.interval)
+	}
+}
+
+func TestForgetLimiter(t *testing.T) {
+	a := &API{Shop: "forgotten-limiter.myshopify.com"}
+	l := a.limiter()
+	ForgetLimiter(a.Shop)
+	if a.limiter() == l {
+		t.Errorf("Expected a new limiter once the shop is forgotten")
+	}
+}
+
+func TestRequestUpdatesLimiterFromHeader(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Shopify-Shop-Api-Call-Limit", "12/80")
+		w.Write([]byte(`{"count": 3}`))
+	})
+	defer srv.Close()
+
+	// nothing leaks out of the bucket during the test
+	a.Limiter.interval = time.Hour
+
+	if _, err := a.ProductsCount(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls, limit := a.limiter().Status(); calls != 12 || limit != 80 {
+		t.Errorf("Expected limiter at 12/80, got %d/%d", calls, limit)
+	}
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	var calls int32
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if atomic.AddInt32(&calls, 1)%2 == 1 {
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(429)
+			return
+		}
+		if !strings.Contains(string(body), `"address":"https://example.com/hook"`) {
+			t.Errorf("Expected retried request to carry the original body, got %q", body)
+		}
+		w.WriteHeader(201)
+		w.Write([]byte(`{"webhook": {"id": 1}}`))
+	})
+	defer srv.Close()
+
+	// more 429s than MAX_RETRIES over the life of the API, but never more
+	// than one per call
+	for i := 0; i < MAX_RETRIES+2; i++ {
+		hook := a.NewWebhook()
+		hook.Address = "https://example.com/hook"
+		if err := hook.Save(nil); err != nil {
+			t.Fatalf("Save %d failed: %v", i, err)
+		}
+	}
+}
+
+func TestRetryServerErrorsOnlyWhenIdempotent(t *testing.T) {
+	var calls int32
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(503)
+	})
+	defer srv.Close()
+	a.MaxRetries = 2
+
+	a.Product(1)
+	if n := atomic.SwapInt32(&calls, 0); n != 3 {
+		t.Errorf("Expected GET to be tried 3 times, got %d", n)
+	}
+
+	a.NewProduct().Save(nil)
+	if n := atomic.SwapInt32(&calls, 0); n != 1 {
+		t.Errorf("Expected POST to be tried once, got %d", n)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d, ok := parseRetryAfter("2.0"); !ok || d != 2*time.Second {
+		t.Errorf("Expected 2s, got %v (%v)", d, ok)
+	}
+	if _, ok := parseRetryAfter(""); ok {
+		t.Errorf("Expected empty header to be ignored")
+	}
+}
+
+func TestResponseErrors(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc-123")
+		if r.Method == "GET" {
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors": "Not Found"}`))
+			return
+		}
+		w.WriteHeader(422)
+		w.Write([]byte(`{"errors": {"title": ["can't be blank"]}}`))
+	})
+	defer srv.Close()
+
+	_, err := a.Product(1)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	err = a.NewProduct().Save(nil)
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation, got %v", err)
+	}
+
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) {
+		t.Fatalf("Expected a *ResponseError, got %T", err)
+	}
+	if respErr.RequestID != "abc-123" || respErr.Endpoint != "/admin/products.json" || respErr.Method != "POST" {
+		t.Errorf("Unexpected error details: %+v", respErr)
+	}
+	if msgs := respErr.Errors["title"]; len(msgs) != 1 || msgs[0] != "can't be blank" {
+		t.Errorf("Expected title validation error, got %v", respErr.Errors)
+	}
+}
+
+func TestUnexpectedStatus(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"product": {"id": 1}}`))
+	})
+	defer srv.Close()
+
+	err := a.NewProduct().Save(nil)
+	if !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("Expected ErrUnexpectedStatus, got %v", err)
+	}
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		t.Errorf("Expected a successful call not to be a *ResponseError")
+	}
+	if err.Error() != "shopify: unexpected status 200 OK" {
+		t.Errorf("Unexpected message %q", err)
+	}
+}
+
+func TestUpdatesExpectOK(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		root := strings.TrimSuffix(strings.Split(r.URL.Path, "/")[2], "s")
+		fmt.Fprintf(w, `{%q: {"id": 1}}`, root)
+	})
+	defer srv.Close()
+
+	page := &Page{Id: 1, api: a}
+	blog := &Blog{Id: 1, api: a}
+	redirect := &Redirect{Id: 1, api: a}
+	theme := &Theme{Id: 1, api: a}
+	for _, save := range []func() error{page.Save, blog.Save, redirect.Save, theme.Save, page.Save} {
+		if err := save(); err != nil {
+			t.Errorf("Unexpected error updating: %v", err)
+		}
+	}
+}
+
+func TestIteratorFollowsLinkHeader(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page_info") {
+		case "":
+			w.Header().Set("Link", `<https://shop/admin/products.json?limit=2&page_info=p2>; rel="next"`)
+			w.Write([]byte(`{"products": [{"id": 1}, {"id": 2}]}`))
+		case "p2":
+			if r.URL.Query().Get("since_id") != "" {
+				t.Errorf("Cursor request must not repeat filters: %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Link", `<https://shop/admin/products.json?limit=2&page_info=p1>; rel="previous"`)
+			w.Write([]byte(`{"products": [{"id": 3}]}`))
+		}
+	})
+	defer srv.Close()
+
+	ids := []int64{}
+	for p, err := range a.ProductsIter(context.Background(), &ProductsOptions{Limit: 2}).All() {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if p.api != a {
+			t.Errorf("Expected product %d to be bound to the API", p.ID)
+		}
+		ids = append(ids, p.ID)
+	}
+	if fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("Expected products 1 2 3, got %v", ids)
+	}
+}
+
+func TestIteratorFollowsSinceID(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("since_id") {
+		case "0":
+			w.Write([]byte(`{"orders": [{"id": 10}, {"id": 11}]}`))
+		case "11":
+			w.Write([]byte(`{"orders": [{"id": 12}]}`))
+		default:
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+	})
+	defer srv.Close()
+
+	it := a.OrdersIter(context.Background(), &OrdersOptions{Limit: 2})
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 orders, got %d", count)
+	}
+}
+
+func TestIteratorSinceIDNeedsIDs(t *testing.T) {
+	var calls int32
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) > 2 {
+			t.Fatalf("Iterator kept requesting %s", r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("fields") != "name,id" {
+			t.Errorf("Expected id to be added to fields, got %s", r.URL.RawQuery)
+		}
+		// a server that ignores the fields option and leaves ids out
+		w.Write([]byte(`{"orders": [{"name": "#1"}, {"name": "#2"}]}`))
+	})
+	defer srv.Close()
+
+	it := a.OrdersIter(context.Background(), &OrdersOptions{Limit: 2, Fields: "name"})
+	for it.Next() {
+	}
+	if it.Err() == nil {
+		t.Errorf("Expected an error once since_id stops advancing")
+	}
+
+	bad := newIterator(context.Background(), a, "/admin/orders.json", "orders", 42, nil, func(v *Order) {})
+	if bad.Next() || bad.Err() == nil {
+		t.Errorf("Expected options that can't be encoded to fail the iterator")
+	}
+}
+
+func TestAPIVersion(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/api/2024-07/products/count.json" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("X-Shopify-API-Version", "2024-10")
+		w.Header().Set("X-Shopify-API-Deprecated-Reason", "https://shopify.dev/changelog")
+		w.Write([]byte(`{"count": 1}`))
+	})
+	defer srv.Close()
+
+	notices := []VersionNotice{}
+	a.APIVersion = "2024-07"
+	a.OnVersionNotice = func(n VersionNotice) {
+		notices = append(notices, n)
+	}
+
+	if _, err := a.ProductsCount(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(notices) != 1 {
+		t.Fatalf("Expected one version notice, got %d", len(notices))
+	}
+	if n := notices[0]; n.Version != "2024-10" || n.RequestedVersion != "2024-07" || n.DeprecatedReason == "" {
+		t.Errorf("Unexpected notice %+v", n)
+	}
+}
+
+func TestGraphQL(t *testing.T) {
+	var calls int32
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/api/2024-07/graphql.json" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("X-Shopify-Access-Token") != "token" {
+			t.Errorf("Expected access token header")
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Write([]byte(`{"errors": [{"message": "Throttled", "extensions": {"code": "THROTTLED"}}],
+				"extensions": {"cost": {"requestedQueryCost": 10, "throttleStatus": {"maximumAvailable": 1000, "currentlyAvailable": 9.99, "restoreRate": 1000}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"shop": {"name": "Test shop"}},
+			"extensions": {"cost": {"requestedQueryCost": 10, "actualQueryCost": 2, "throttleStatus": {"maximumAvailable": 1000, "currentlyAvailable": 998, "restoreRate": 50}}}}`))
+	})
+	defer srv.Close()
+	a.APIVersion = "2024-07"
+
+	out := struct {
+		Shop struct {
+			Name string `json:"name"`
+		} `json:"shop"`
+	}{}
+	cost, err := a.GraphQL(context.Background(), `{ shop { name } }`, nil, &out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected throttled query to be retried, got %d calls", calls)
+	}
+	if out.Shop.Name != "Test shop" {
+		t.Errorf("Expected shop name to be decoded, got %q", out.Shop.Name)
+	}
+	if cost == nil || cost.ActualQueryCost != 2 || cost.ThrottleStatus.CurrentlyAvailable != 998 {
+		t.Errorf("Unexpected cost %+v", cost)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": null, "errors": [{"message": "Access denied for orders field.", "extensions": {"code": "ACCESS_DENIED"}}]}`))
+	})
+	defer srv.Close()
+
+	_, err := a.GraphQL(context.Background(), `{ orders(first: 1) { edges { node { id } } } }`, nil, nil)
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	var gqlErrs GraphQLErrors
+	if !errors.As(err, &gqlErrs) || gqlErrs[0].Code() != "ACCESS_DENIED" {
+		t.Errorf("Expected GraphQLErrors, got %#v", err)
+	}
+}
+
+func TestBulkOperation(t *testing.T) {
+	var srvURL string
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/results.jsonl" {
+			w.Write([]byte(`{"id":"gid://shopify/Product/1","title":"Shirt","productType":"shirts","descriptionHtml":"<p>Hi</p>","tags":["a","b"]}
+{"id":"gid://shopify/ProductVariant/11","sku":"SHIRT-S","__parentId":"gid://shopify/Product/1"}
+{"id":"gid://shopify/Collection/5","title":"Summer"}
+`))
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "bulkOperationRunQuery") {
+			w.Write([]byte(`{"data": {"bulkOperationRunQuery": {"bulkOperation": {"id": "gid://shopify/BulkOperation/9", "status": "CREATED"}, "userErrors": []}}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"node": {"id": "gid://shopify/BulkOperation/9", "status": "COMPLETED", "url": "` + srvURL + `/results.jsonl"}}}`))
+	})
+	defer srv.Close()
+	srvURL = srv.URL
+
+	ctx := context.Background()
+	op, err := a.BulkQuery(ctx, `{ products { edges { node { id title } } } }`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = op.Wait(ctx, time.Millisecond); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	products := []*Product{}
+	variants := []*Variant{}
+	others := []string{}
+	err = op.Stream(ctx, BulkHandler{
+		Product: func(p *Product) error { products = append(products, p); return nil },
+		Variant: func(v *Variant) error { variants = append(variants, v); return nil },
+		Record:  func(r *BulkRecord) error { others = append(others, r.Type()); return nil },
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(products) != 1 || products[0].ID != 1 || products[0].ProductType != "shirts" || products[0].BodyHtml != "<p>Hi</p>" || products[0].Tags != "a, b" {
+		t.Errorf("Unexpected products %+v", products)
+	}
+	if len(variants) != 1 || variants[0].Id != 11 || variants[0].ProductId != 1 || variants[0].Sku != "SHIRT-S" {
+		t.Errorf("Unexpected variants %+v", variants)
+	}
+	if fmt.Sprint(others) != "[Collection]" {
+		t.Errorf("Expected the collection as a raw record, got %v", others)
+	}
+}
+
+func TestOrdersOptionsAndCancel(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /admin/orders/count.json":
+			if r.URL.Query().Get("status") != "any" || r.URL.Query().Get("created_at_min") != "2024-07-01T00:00:00Z" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"count": 2}`))
+		case "GET /admin/orders/7.json":
+			w.Write([]byte(`{"order": {"id": 7, "line_items": [{"id": 1, "quantity": 2}, {"id": 2, "quantity": 1}], "refunds": [{"refund_line_items": [{"line_item_id": 1, "quantity": 1}]}]}}`))
+		case "POST /admin/orders/7/refunds/calculate.json":
+			body := map[string]Refund{}
+			json.NewDecoder(r.Body).Decode(&body)
+			items := body["refund"].RefundLineItems
+			if len(items) != 2 || items[0].LineItemId != 1 || items[0].Quantity != 1 || items[1].LineItemId != 2 || items[1].Quantity != 1 || !body["refund"].Shipping.FullRefund {
+				t.Errorf("Unexpected refund calculation %+v", body["refund"])
+			}
+			w.Write([]byte(`{"refund": {"transactions": [{"parent_id": 3, "amount": "12.50", "kind": "suggested_refund", "gateway": "shopify_payments"}]}}`))
+		case "POST /admin/orders/7/cancel.json":
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["reason"] != OrderCancelCustomer || body["amount"] != nil || body["restock"] != true {
+				t.Errorf("Unexpected cancel body: %v", body)
+			}
+			refund, _ := json.Marshal(body["refund"])
+			if !strings.Contains(string(refund), `"transactions":[{"amount":"12.50","gateway":"shopify_payments","kind":"refund","parent_id":3}]`) {
+				t.Errorf("Unexpected cancel refund: %s", refund)
+			}
+			w.Write([]byte(`{"order": {"id": 7, "cancel_reason": "customer", "cancelled_at": "2024-07-02T10:00:00-04:00"}}`))
+		case "PUT /admin/orders/7.json":
+			w.Write([]byte(`{"order": {"id": 7, "note": "called"}}`))
+		case "GET /admin/orders/8.json":
+			w.Write([]byte(`{"order": {"id": 8, "financial_status": "pending", "line_items": [{"id": 1, "quantity": 1}]}}`))
+		case "GET /admin/orders/9.json":
+			w.Write([]byte(`{"order": {"id": 9, "financial_status": "paid", "line_items": [{"id": 1, "quantity": 1}]}}`))
+		case "POST /admin/orders/9/refunds/calculate.json":
+			w.Write([]byte(`{"refund": {"transactions": [{"parent_id": 3, "amount": "0.00", "kind": "suggested_refund"}]}}`))
+		case "POST /admin/orders/8/cancel.json", "POST /admin/orders/9/cancel.json":
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if _, ok := body["refund"]; ok {
+				t.Errorf("Expected no refund cancelling an order with nothing to refund, got %v", body)
+			}
+			w.Write([]byte(`{"order": {"id": 8, "cancelled_at": "2024-07-02T10:00:00-04:00"}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	count, err := a.OrdersCount(&OrdersCountOptions{Status: "any", CreatedAtMin: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil || count != 2 {
+		t.Fatalf("Expected 2 orders, got %d, %v", count, err)
+	}
+
+	order := a.NewOrder()
+	order.Id = 7
+	if err = order.Cancel(OrderCancelCustomer, true, true, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if order.CancelledAt.IsZero() || order.CancelReason != "customer" {
+		t.Errorf("Expected the order to be reloaded, got %+v", order)
+	}
+
+	order.Note = "called"
+	if err = order.Save(); err != nil {
+		t.Fatalf("Unexpected error saving an existing order: %v", err)
+	}
+	for _, id := range []int64{8, 9} {
+		unpaid := a.NewOrder()
+		unpaid.Id = id
+		if err = unpaid.Cancel(OrderCancelCustomer, true, false, false); err != nil {
+			t.Fatalf("Unexpected error cancelling order %d: %v", id, err)
+		}
+		if unpaid.CancelledAt.IsZero() {
+			t.Errorf("Expected order %d to be cancelled, got %+v", id, unpaid)
+		}
+	}
+}
+
+func TestFulfillments(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.Method + " " + r.URL.Path {
+		case "POST /admin/orders/7/fulfillments.json":
+			f := body["fulfillment"]
+			if f["location_id"] != float64(3) || f["tracking_company"] != "UPS" || fmt.Sprint(f["tracking_numbers"]) != "[1Z999]" {
+				t.Errorf("Unexpected fulfillment body: %v", f)
+			}
+			w.WriteHeader(201)
+			w.Write([]byte(`{"fulfillment": {"id": 5, "order_id": 7, "status": "pending", "tracking_numbers": ["1Z999"]}}`))
+		case "PUT /admin/orders/7/fulfillments/5.json":
+			f := body["fulfillment"]
+			if fmt.Sprintf("%v %v %v %v", f["tracking_numbers"], f["tracking_urls"], f["tracking_company"], f["notify_customer"]) != "[1Z000] [] UPS false" {
+				t.Errorf("Unexpected tracking body: %v", body)
+			}
+			w.Write([]byte(`{"fulfillment": {"id": 5, "order_id": 7, "status": "pending", "tracking_number": "1Z000"}}`))
+		case "POST /admin/orders/7/fulfillments/5/complete.json":
+			w.Write([]byte(`{"fulfillment": {"id": 5, "order_id": 7, "status": "success"}}`))
+		case "GET /admin/orders/7.json":
+			w.Write([]byte(`{"order": {"id": 7, "fulfillments": [{"id": 5, "order_id": 7, "status": "pending"}]}}`))
+		case "GET /admin/orders/7/fulfillment_orders.json":
+			w.Write([]byte(`{"fulfillment_orders": [{"id": 9, "order_id": 7, "assigned_location_id": 3, "status": "open"}]}`))
+		case "POST /admin/fulfillment_orders/9/move.json":
+			if body["fulfillment_order"]["new_location_id"] != float64(4) {
+				t.Errorf("Unexpected move body: %v", body)
+			}
+			w.Write([]byte(`{"original_fulfillment_order": {"id": 9, "status": "closed"}, "moved_fulfillment_order": {"id": 10, "assigned_location_id": 4, "status": "open"}, "remaining_fulfillment_order": null}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	order := a.NewOrder()
+	order.Id = 7
+
+	f := order.NewFulfillment()
+	f.LocationId = 3
+	f.TrackingCompany = "UPS"
+	f.TrackingNumbers = []string{"1Z999"}
+	if err := f.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.Id != 5 {
+		t.Errorf("Expected the fulfillment to be reloaded, got %+v", f)
+	}
+	if err := f.UpdateTracking("1Z000", "", "UPS", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := f.Complete(); err != nil || f.Status != "success" {
+		t.Fatalf("Expected a successful fulfillment, got %q, %v", f.Status, err)
+	}
+
+	loaded, err := a.Order(7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = loaded.Fulfillments[0].Complete(); err != nil || loaded.Fulfillments[0].Status != "success" {
+		t.Fatalf("Expected the order's fulfillment to complete, got %q, %v", loaded.Fulfillments[0].Status, err)
+	}
+
+	fos, err := order.FulfillmentOrders()
+	if err != nil || len(fos) != 1 {
+		t.Fatalf("Expected one fulfillment order, got %d, %v", len(fos), err)
+	}
+	moved, err := fos[0].Move(4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if moved.Id != 10 || moved.AssignedLocationId != 4 || fos[0].Status != "closed" {
+		t.Errorf("Unexpected move result: %+v, original %+v", moved, fos[0])
+	}
+}
+
+func TestRefundsAndTransactions(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.Method + " " + r.URL.Path {
+		case "POST /admin/orders/7/refunds/calculate.json":
+			items := fmt.Sprint(body["refund"]["refund_line_items"])
+			if items != "[map[line_item_id:11 location_id:3 quantity:1 restock_type:return]]" {
+				t.Errorf("Unexpected refund line items: %s", items)
+			}
+			w.Write([]byte(`{"refund": {"refund_line_items": [{"line_item_id": 11, "quantity": 1, "subtotal": "10.10"}], "transactions": [{"parent_id": 20, "amount": "10.10", "kind": "suggested_refund", "gateway": "bogus"}]}}`))
+		case "POST /admin/orders/7/refunds.json":
+			tx := body["refund"]["transactions"].([]interface{})[0].(map[string]interface{})
+			if tx["kind"] != "refund" || tx["amount"] != "10.10" {
+				t.Errorf("Unexpected refund transaction: %v", tx)
+			}
+			w.WriteHeader(201)
+			w.Write([]byte(`{"refund": {"id": 30, "order_id": 7, "transactions": [{"id": 21, "kind": "refund", "amount": "10.10", "status": "success"}]}}`))
+		case "GET /admin/orders/7/transactions.json":
+			w.Write([]byte(`{"transactions": [{"id": 20, "order_id": 7, "kind": "authorization", "amount": "25.00"}]}`))
+		case "GET /admin/orders/7.json":
+			w.Write([]byte(`{"order": {"id": 7, "refunds": [{"id": 30, "order_id": 7, "transactions": [{"id": 20, "order_id": 7, "kind": "authorization", "amount": "25.00"}]}]}}`))
+		case "GET /admin/orders/7/refunds.json":
+			w.Write([]byte(`{"refunds": [{"id": 30, "order_id": 7, "transactions": [{"id": 20, "order_id": 7, "kind": "authorization", "amount": "25.00"}]}]}`))
+		case "POST /admin/orders/7/transactions.json":
+			tx := body["transaction"]
+			if tx["kind"] != "capture" || tx["parent_id"] != float64(20) || tx["amount"] != "5.00" {
+				t.Errorf("Unexpected capture: %v", tx)
+			}
+			w.WriteHeader(201)
+			w.Write([]byte(`{"transaction": {"id": 22, "order_id": 7, "kind": "capture", "amount": "5.00", "status": "success"}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	order := a.NewOrder()
+	order.Id = 7
+
+	refund := &Refund{}
+	refund.AddLineItem(11, 1, RestockReturn, &Location{Id: 3})
+	calculated, err := order.CalculateRefund(refund)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !calculated.RefundLineItems[0].Subtotal.Equal(MustParseMoney("10.1", "")) {
+		t.Errorf("Unexpected subtotal %s", calculated.RefundLineItems[0].Subtotal)
+	}
+
+	refund.Transactions = calculated.Transactions
+	refund.Transactions[0].Kind = TransactionRefund
+	if err = order.CreateRefund(refund); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if refund.Id != 30 || refund.Transactions[0].Status != "success" {
+		t.Errorf("Expected the created refund, got %+v", refund)
+	}
+
+	txs, err := order.Transactions()
+	if err != nil || len(txs) != 1 {
+		t.Fatalf("Expected one transaction, got %d, %v", len(txs), err)
+	}
+	capture, err := txs[0].Capture(MustParseMoney("5.00", ""))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if capture.Id != 22 || capture.Kind != TransactionCapture {
+		t.Errorf("Unexpected capture %+v", capture)
+	}
+
+	loaded, err := a.Order(7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = loaded.Refunds[0].Transactions[0].Capture(MustParseMoney("5.00", "")); err != nil {
+		t.Fatalf("Expected the order's refund transactions to be bound, got %v", err)
+	}
+
+	refunds, err := order.ListRefunds()
+	if err != nil || len(refunds) != 1 || refunds[0].Id != 30 {
+		t.Fatalf("Expected one refund, got %+v, %v", refunds, err)
+	}
+	if _, err = refunds[0].Transactions[0].Capture(MustParseMoney("5.00", "")); err != nil {
+		t.Fatalf("Expected the listed refund transactions to be bound, got %v", err)
+	}
+}
+
+func TestProductUploadImages(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(dir+"/RED-M.png", []byte("red"), 0644)
+	os.WriteFile(dir+"/cover.jpg", []byte("cover"), 0644)
+	os.WriteFile(dir+"/notes.txt", []byte("skip me"), 0644)
+
+	uploaded := map[string]string{}
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/admin/products/1/images.json" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		body := map[string]ProductImage{}
+		json.NewDecoder(r.Body).Decode(&body)
+		image := body["image"]
+		uploaded[image.Filename] = fmt.Sprint(image.VariantIds)
+		if image.Attachment == "" {
+			t.Errorf("Expected %s to be attached", image.Filename)
+		}
+		fmt.Fprintf(w, `{"image": {"id": %d, "product_id": 1, "variant_ids": %v}}`, len(uploaded), image.VariantIds)
+	})
+	defer srv.Close()
+
+	product := a.NewProduct()
+	product.ID = 1
+	product.Variants = []Variant{{Id: 10, Sku: "RED-M"}, {Id: 11, Sku: "BLUE-M"}}
+
+	images, err := product.UploadImages(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(images) != 2 || len(product.Images) != 2 {
+		t.Fatalf("Expected 2 uploaded images, got %d", len(images))
+	}
+	if uploaded["RED-M.png"] != "[10]" || uploaded["cover.jpg"] != "[]" {
+		t.Errorf("Unexpected variant mapping: %v", uploaded)
+	}
+}
+
+func TestFindVariantsBySKUAndSave(t *testing.T) {
+	puts := []string{}
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /admin/products.json":
+			if r.URL.Query().Get("fields") != "id,variants" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			switch r.URL.Query().Get("since_id") {
+			case "0":
+				w.Write([]byte(`{"products": [{"id": 1, "variants": [{"id": 10, "product_id": 1, "sku": "A", "price": "5.00"}, {"id": 11, "product_id": 1, "sku": "B"}]}]}`))
+			default:
+				w.Write([]byte(`{"products": []}`))
+			}
+		case "PUT /admin/variants/10.json":
+			body := map[string]map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["variant"]["price"] != "6.50" {
+				t.Errorf("Unexpected variant body: %v", body)
+			}
+			w.Write([]byte(`{"variant": {"id": 10, "product_id": 1, "sku": "A", "price": "6.50"}}`))
+		case "GET /admin/variants/12.json":
+			w.Write([]byte(`{"variant": {"id": 12, "price": "5.00", "inventory_quantity": 5, "old_inventory_quantity": 5}}`))
+		case "PUT /admin/variants/12.json":
+			body := map[string]map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			puts = append(puts, fmt.Sprintf("%v %v", body["variant"]["inventory_quantity"], body["variant"]["old_inventory_quantity"]))
+			w.Write([]byte(`{"variant": {"id": 12, "price": "6.00", "inventory_quantity": 5, "old_inventory_quantity": 5}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	variants, err := a.FindVariantsBySKU(context.Background(), "A", "C")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(variants) != 1 || variants[0].Id != 10 {
+		t.Fatalf("Expected variant 10, got %+v", variants)
+	}
+
+	v := variants[0]
+	v.Price = MustParseMoney("6.50", "")
+	if err = v.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v.Price.String() != "6.50" || v.api != a {
+		t.Errorf("Unexpected saved variant %+v", v)
+	}
+	loaded, err := a.Variant(12)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loaded.Price = MustParseMoney("6.00", "")
+	if err = loaded.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	quantity := int64(8)
+	loaded.InventoryQuantity = &quantity
+	if err = loaded.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(puts) != "[<nil> <nil> 8 5]" {
+		t.Errorf("Expected stock sent only once changed, got %v", puts)
+	}
+}
+
+func TestSyncInventory(t *testing.T) {
+	calls := []string{}
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /admin/products.json":
+			if r.URL.Query().Get("since_id") != "0" {
+				w.Write([]byte(`{"products": []}`))
+				return
+			}
+			w.Write([]byte(`{"products": [{"id": 1, "variants": [{"id": 10, "sku": "A", "inventory_item_id": 100}, {"id": 11, "sku": "B", "inventory_item_id": 101}]}]}`))
+		case "GET /admin/inventory_levels.json":
+			if r.URL.Query().Get("inventory_item_ids") != "100,101" || r.URL.Query().Get("location_ids") != "1,2" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"inventory_levels": [
+				{"inventory_item_id": 100, "location_id": 1, "available": 5},
+				{"inventory_item_id": 100, "location_id": 2, "available": 3},
+				{"inventory_item_id": 101, "location_id": 1, "available": 0}
+			]}`))
+		case "POST /admin/inventory_levels/adjust.json", "POST /admin/inventory_levels/set.json":
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			calls = append(calls, fmt.Sprintf("%s %v@%v %v%v", strings.TrimSuffix(r.URL.Path[len("/admin/inventory_levels/"):], ".json"),
+				body["inventory_item_id"], body["location_id"], body["available_adjustment"], body["available"]))
+			fmt.Fprintf(w, `{"inventory_level": {"inventory_item_id": %v, "location_id": %v}}`, body["inventory_item_id"], body["location_id"])
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	levels, err := a.SyncInventory(context.Background(), map[string]map[int64]int64{
+		"A": {1: 5, 2: 1},
+		"B": {1: 4, 2: 2},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "[adjust 100@2 -2<nil> adjust 101@1 4<nil> set 101@2 <nil>2]"
+	if fmt.Sprint(calls) != expected {
+		t.Errorf("Expected calls %s, got %v", expected, calls)
+	}
+	if len(levels) != 3 {
+		t.Errorf("Expected 3 changed levels, got %d", len(levels))
+	}
+
+	if _, err = a.SyncInventory(context.Background(), map[string]map[int64]int64{"Z": {1: 1}}); err == nil {
+		t.Errorf("Expected an error for an unknown SKU")
+	}
+}
+
+func TestInventoryItemSave(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /admin/inventory_items/4.json":
+			b, _ := io.ReadAll(r.Body)
+			if strings.TrimSpace(string(b)) != `{"inventory_item":{"cost":"3.20","id":4}}` {
+				t.Errorf("Expected only the cost to be sent, got %s", b)
+			}
+			w.Write([]byte(`{"inventory_item": {"id": 4, "cost": "3.20", "tracked": true, "requires_shipping": true}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	item := &InventoryItem{Id: 4, Cost: MustParseMoney("3.20", ""), api: a}
+	if err := item.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if item.Tracked == nil || !*item.Tracked {
+		t.Errorf("Expected the item to stay tracked, got %+v", item)
+	}
+}
+
+func TestCustomerSearchAndAddresses(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /admin/customers/search.json":
+			if r.URL.Query().Get("query") != "country:Canada orders_count:>2" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"customers": [{"id": 3, "addresses": [{"id": 8, "customer_id": 3}, {"id": 9, "customer_id": 3, "default": true}]}]}`))
+		case "PUT /admin/customers/3/addresses/8/default.json":
+			w.Write([]byte(`{"customer_address": {"id": 8, "customer_id": 3, "default": true}}`))
+		case "GET /admin/customer_saved_searches/5.json":
+			w.Write([]byte(`{"customer_saved_search": {"id": 5, "name": "Repeat", "query": "orders_count:>1"}}`))
+		case "GET /admin/customer_saved_searches/5/customers.json":
+			w.Write([]byte(`{"customers": [{"id": 3}]}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	customers, err := a.SearchCustomers("country:Canada orders_count:>2")
+	if err != nil || len(customers) != 1 {
+		t.Fatalf("Expected one customer, got %d, %v", len(customers), err)
+	}
+
+	address := &customers[0].Addresses[0]
+	if err = address.SetDefault(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !address.Default {
+		t.Errorf("Expected address 8 to be the default")
+	}
+
+	search, err := a.CustomerSavedSearch(5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if search.Name != "Repeat" || search.Query != "orders_count:>1" {
+		t.Errorf("Unexpected saved search %+v", search)
+	}
+	if customers, err = search.Customers(); err != nil || len(customers) != 1 {
+		t.Errorf("Expected one customer, got %d, %v", len(customers), err)
+	}
+}
+
+func TestCustomCollectionMembership(t *testing.T) {
+	deleted := []string{}
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /admin/collects.json":
+			if r.URL.Query().Get("collection_id") != "4" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"collects": [{"id": 11, "collection_id": 4, "product_id": 1, "position": 2}, {"id": 12, "collection_id": 4, "product_id": 2, "position": 1}]}`))
+		case "POST /admin/collects.json":
+			body := map[string]Collect{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["collect"].ProductId != 3 || body["collect"].CollectionId != 4 {
+				t.Errorf("Unexpected collect %+v", body["collect"])
+			}
+			w.WriteHeader(201)
+			w.Write([]byte(`{"collect": {"id": 13, "collection_id": 4, "product_id": 3}}`))
+		case "DELETE /admin/collects/11.json":
+			deleted = append(deleted, r.URL.Path)
+			w.Write([]byte(`{}`))
+		case "PUT /admin/custom_collections/4.json":
+			body := map[string]map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			got := fmt.Sprintf("%v %v", body["custom_collection"]["sort_order"], body["custom_collection"]["collects"])
+			if got != "manual [map[position:1 product_id:1] map[position:2 product_id:2]]" {
+				t.Errorf("Unexpected reorder %s", got)
+			}
+			w.Write([]byte(`{"custom_collection": {"id": 4, "sort_order": "manual"}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	collection := &CustomCollection{Id: 4, api: a}
+
+	added, err := collection.AddProducts(2, 3)
+	if err != nil || len(added) != 1 || added[0].Id != 13 {
+		t.Fatalf("Expected collect 13 to be added, got %+v, %v", added, err)
+	}
+
+	if err = collection.RemoveProducts(1, 5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(deleted) != 1 {
+		t.Errorf("Expected collect 11 to be deleted, got %v", deleted)
+	}
+
+	if err = collection.Reorder(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if collection.SortOrder != CollectionSortManual || collection.api != a {
+		t.Errorf("Unexpected collection %+v", collection)
+	}
+
+	if err = collection.Reorder(2, 9); err == nil {
+		t.Errorf("Expected an error reordering a product outside the collection")
+	}
+}
+
+func TestSmartCollectionSave(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /admin/smart_collections.json":
+			w.WriteHeader(201)
+			w.Write([]byte(`{"smart_collection": {"id": 6, "title": "Acme"}}`))
+		case "PUT /admin/smart_collections/6.json":
+			w.Write([]byte(`{"smart_collection": {"id": 6, "title": "Acme goods"}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	collection := a.NewSmartCollection()
+	collection.Title = "Acme"
+	collection.Rules = []Rule{{Column: RuleColumnVendor, Relation: RuleRelationEquals, Condition: "Acme"}}
+	if err := collection.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	collection.Title = "Acme goods"
+	for i := 0; i < 2; i++ {
+		if err := collection.Save(); err != nil {
+			t.Fatalf("Unexpected error updating the collection: %v", err)
+		}
+	}
+	if collection.Title != "Acme goods" || collection.api != a {
+		t.Errorf("Unexpected collection %+v", collection)
+	}
+}
+
+func TestDraftOrderComplete(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /admin/draft_orders.json":
+			body := map[string]map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			items := fmt.Sprint(body["draft_order"]["line_items"])
+			if items != "[map[applied_discount:map[value:10 value_type:percentage] quantity:2 variant_id:10]]" {
+				t.Errorf("Unexpected line items: %s", items)
+			}
+			if customer := fmt.Sprint(body["draft_order"]["customer"]); customer != "map[id:3]" {
+				t.Errorf("Expected only the customer id to be sent, got %s", customer)
+			}
+			w.WriteHeader(201)
+			w.Write([]byte(`{"draft_order": {"id": 4, "status": "open", "line_items": [{"variant_id": 10, "quantity": 2, "applied_discount": {"value": "10.0", "value_type": "percentage", "amount": "3.00"}}]}}`))
+		case "POST /admin/draft_orders/4/send_invoice.json":
+			w.WriteHeader(201)
+			w.Write([]byte(`{"draft_order_invoice": {"to": "buyer@example.com"}}`))
+		case "PUT /admin/draft_orders/4/complete.json":
+			if r.URL.Query().Get("payment_pending") != "true" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"draft_order": {"id": 4, "status": "completed", "order_id": 7}}`))
+		case "GET /admin/orders/7.json":
+			w.Write([]byte(`{"order": {"id": 7, "financial_status": "pending"}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	draft := a.NewDraftOrder()
+	draft.LineItems = []LineItem{{
+		VariantId:       10,
+		Quantity:        2,
+		AppliedDiscount: &AppliedDiscount{Value: "10", ValueType: DiscountPercentage},
+	}}
+	draft.Customer = &Customer{Id: 3, Email: "buyer@example.com"}
+	if err := draft.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if draft.LineItems[0].AppliedDiscount.Amount.String() != "3.00" {
+		t.Errorf("Unexpected discount %+v", draft.LineItems[0].AppliedDiscount)
+	}
+
+	if err := draft.SendInvoice("buyer@example.com", "", "Your quote"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	order, err := draft.Complete(true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if order.Id != 7 || draft.Status != DraftOrderCompleted {
+		t.Errorf("Unexpected completion: order %+v, draft %+v", order, draft)
+	}
+}
+
+func TestImportDiscountCodes(t *testing.T) {
+	var batches, polls int32
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /admin/price_rules/2/batch.json":
+			body := map[string][]map[string]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			n := atomic.AddInt32(&batches, 1)
+			if (n == 1 && len(body["discount_codes"]) != 100) || (n == 2 && len(body["discount_codes"]) != 5) {
+				t.Errorf("Unexpected batch %d of %d codes", n, len(body["discount_codes"]))
+			}
+			w.WriteHeader(201)
+			fmt.Fprintf(w, `{"discount_code_creation": {"id": %d, "price_rule_id": 2, "status": "queued"}}`, n)
+		case "GET /admin/price_rules/2/batch/1.json":
+			atomic.AddInt32(&polls, 1)
+			w.Write([]byte(`{"discount_code_creation": {"id": 1, "price_rule_id": 2, "status": "completed", "failed_count": 0}}`))
+		case "GET /admin/price_rules/2/batch/2.json":
+			atomic.AddInt32(&polls, 1)
+			w.Write([]byte(`{"discount_code_creation": {"id": 2, "price_rule_id": 2, "status": "completed", "failed_count": 1}}`))
+		case "GET /admin/price_rules/2/batch/2/discount_codes.json":
+			w.Write([]byte(`{"discount_codes": [{"id": 50, "code": "OK"}, {"code": "TAKEN", "errors": {"code": ["must be unique"]}}]}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	codes, err := (&DiscountCodeGenerator{}).Generate(105)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rule := a.NewPriceRule()
+	rule.Id = 2
+	failed, err := rule.ImportDiscountCodesCtx(ctx, codes)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(failed) != 1 || failed[0].Code != "TAKEN" || failed[0].Errors["code"][0] != "must be unique" {
+		t.Errorf("Unexpected failed codes %+v", failed)
+	}
+	if batches != 2 || polls != 2 {
+		t.Errorf("Expected 2 batches polled once each, got %d and %d polls", batches, polls)
+	}
+}
+
+func TestGiftCards(t *testing.T) {
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /admin/gift_cards.json":
+			body := map[string]map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["gift_card"]["code"] != "abcd1234efgh5678" || body["gift_card"]["initial_value"] != "25.00" {
+				t.Errorf("Expected the full code to be sent, got %v", body)
+			}
+			w.WriteHeader(201)
+			w.Write([]byte(`{"gift_card": {"id": 1, "code": "abcd1234efgh5678", "last_characters": "5678", "initial_value": "25.00", "balance": "25.00"}}`))
+		case "POST /admin/gift_cards/1/adjustments.json":
+			body := map[string]map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["adjustment"]["amount"] != "-5.00" {
+				t.Errorf("Unexpected adjustment %v", body)
+			}
+			w.WriteHeader(201)
+			w.Write([]byte(`{"adjustment": {"id": 3, "gift_card_id": 1, "amount": "-5.00"}}`))
+		case "GET /admin/gift_cards/1.json":
+			w.Write([]byte(`{"gift_card": {"id": 1, "last_characters": "5678", "balance": "20.00"}}`))
+		case "PUT /admin/gift_cards/1.json":
+			body := map[string]map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if expires, ok := body["gift_card"]["expires_on"]; !ok || expires != nil {
+				t.Errorf("Expected expires_on to be sent as null, got %v", body)
+			}
+			w.Write([]byte(`{"gift_card": {"id": 1, "last_characters": "5678", "balance": "20.00", "expires_on": null}}`))
+		case "GET /admin/gift_cards/count.json":
+			if r.URL.Query().Get("status") != GiftCardEnabled {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"count": 4}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	card := a.NewGiftCard()
+	card.Code = "abcd1234efgh5678"
+	card.InitialValue = MustParseMoney("25.00", "")
+	if err := card.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		if out := fmt.Sprintf(format, card); strings.Contains(out, "abcd1234") {
+			t.Errorf("%s exposed the gift card code: %s", format, out)
+		}
+	}
+	if out, err := json.Marshal(card); err != nil || !strings.Contains(string(out), `"code":"•••• •••• •••• 5678"`) {
+		t.Errorf("Expected JSON to mask the gift card code, got %s, %v", out, err)
+	}
+	if card.Code.Reveal() != "abcd1234efgh5678" || card.MaskedCode() != "•••• •••• •••• 5678" {
+		t.Errorf("Unexpected code %s", card.MaskedCode())
+	}
+
+	if _, err := card.Adjust(MustParseMoney("-5.00", ""), "loyalty reconciliation"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if card.Balance.String() != "20.00" || card.Code.Reveal() != "abcd1234efgh5678" {
+		t.Errorf("Expected the reloaded balance and the code to be kept, got %s", card.Balance)
+	}
+
+	card.ExpiresOn = Timestamp{}
+	if err := card.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count, err := a.GiftCardsCount(GiftCardEnabled); err != nil || count != 4 {
+		t.Errorf("Expected 4 gift cards, got %d, %v", count, err)
+	}
+}
+
+func TestThemeSync(t *testing.T) {
+	checksum := func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	var uploaded, deleted []string
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("asset[key]")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /admin/themes/7/assets.json":
+			switch key {
+			case "":
+				fmt.Fprintf(w, `{"assets": [{"key": "layout/theme.liquid", "checksum": %q}, {"key": "assets/logo.png", "checksum": %q}, {"key": "config/settings_data.json"}, {"key": "templates/old liquid.liquid", "checksum": %q}, {"key": "../escape.liquid"}]}`,
+					checksum("{{ content_for_layout }}"), checksum("\x89PNG\x00"), checksum("old"))
+			case "layout/theme.liquid":
+				w.Write([]byte(`{"asset": {"key": "layout/theme.liquid", "value": "{{ content_for_layout }}"}}`))
+			case "assets/logo.png":
+				w.Write([]byte(`{"asset": {"key": "assets/logo.png", "attachment": "iVBORwA="}}`))
+			case "templates/old liquid.liquid":
+				w.Write([]byte(`{"asset": {"key": "templates/old liquid.liquid", "value": "old"}}`))
+			case "config/settings_data.json":
+				w.Write([]byte(`{"asset": {"key": "config/settings_data.json", "value": "{}"}}`))
+			default:
+				t.Errorf("Unexpected asset %s", key)
+			}
+		case "PUT /admin/themes/7/assets.json":
+			body := map[string]AssetUpload{}
+			json.NewDecoder(r.Body).Decode(&body)
+			uploaded = append(uploaded, body["asset"].Key+"="+body["asset"].Value)
+			w.Write([]byte(`{"asset": {}}`))
+		case "DELETE /admin/themes/7/assets.json":
+			deleted = append(deleted, key)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ts := a.NewThemeSync(7, dir)
+
+	pulled, err := ts.Pull(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(pulled) != "[assets/logo.png layout/theme.liquid templates/old liquid.liquid]" {
+		t.Errorf("Unexpected pull %v", pulled)
+	}
+	if logo, _ := os.ReadFile(filepath.Join(dir, "assets", "logo.png")); string(logo) != "\x89PNG\x00" {
+		t.Errorf("Unexpected logo %q", logo)
+	}
+
+	os.WriteFile(filepath.Join(dir, "layout", "theme.liquid"), []byte("changed"), 0644)
+	os.Remove(filepath.Join(dir, "templates", "old liquid.liquid"))
+	os.MkdirAll(filepath.Join(dir, "snippets"), 0755)
+	os.WriteFile(filepath.Join(dir, "snippets", "new.liquid"), []byte("new"), 0644)
+	os.MkdirAll(filepath.Join(dir, "config"), 0755)
+	os.WriteFile(filepath.Join(dir, "config", "settings_data.json"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a theme file"), 0644)
+
+	diff, err := ts.Diff(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff.String() != "+ snippets/new.liquid\n~ layout/theme.liquid\n- templates/old liquid.liquid\n" {
+		t.Errorf("Unexpected diff:\n%s", diff)
+	}
+
+	// settings_data.json comes without a checksum, so it is compared by
+	// content, which matches.
+	unignored := a.NewThemeSync(7, dir)
+	unignored.Ignore = nil
+	if all, err := unignored.Diff(context.Background()); err != nil || all.String() != diff.String() {
+		t.Errorf("Expected the same diff with settings_data.json unchanged, got %v:\n%s", err, all)
+	}
+
+	if _, err = ts.Push(context.Background(), diff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(uploaded) != "[snippets/new.liquid=new layout/theme.liquid=changed]" {
+		t.Errorf("Unexpected uploads %v", uploaded)
+	}
+	if fmt.Sprint(deleted) != "[templates/old liquid.liquid]" {
+		t.Errorf("Unexpected deletes %v", deleted)
+	}
+
+	deleted = nil
+	missing := a.NewThemeSync(7, filepath.Join(dir, "missing"))
+	if _, err = missing.Push(context.Background(), nil); err == nil {
+		t.Errorf("Expected an error pushing a missing directory")
+	}
+	if _, err = a.NewThemeSync(7, t.TempDir()).Diff(context.Background()); err == nil {
+		t.Errorf("Expected an error diffing a directory without theme directories")
+	}
+
+	empty := t.TempDir()
+	os.MkdirAll(filepath.Join(empty, "layout"), 0755)
+	ts = a.NewThemeSync(7, empty)
+	if _, err = ts.Push(context.Background(), nil); err == nil {
+		t.Errorf("Expected an error deleting every asset")
+	}
+	if len(deleted) != 0 {
+		t.Errorf("Expected nothing deleted, got %v", deleted)
+	}
+	ts.AllowDeleteAll = true
+	if _, err = ts.Push(context.Background(), nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(deleted) != 3 {
+		t.Errorf("Expected every asset deleted, got %v", deleted)
+	}
+
+	deleted = nil
+	ts = a.NewThemeSync(7, t.TempDir())
+	ts.AllowDeleteAll = true
+	if _, err = ts.Push(context.Background(), nil); err != nil {
+		t.Fatalf("Unexpected error pushing a directory without theme directories: %v", err)
+	}
+	if len(deleted) != 3 {
+		t.Errorf("Expected every asset deleted, got %v", deleted)
+	}
+	ts.Dir = filepath.Join(dir, "missing")
+	if _, err = ts.Push(context.Background(), nil); err == nil {
+		t.Errorf("Expected an error pushing a missing directory")
+	}
+}
+
+func TestThemeWatch(t *testing.T) {
+	var mu sync.Mutex
+	uploads := map[string]string{}
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /admin/themes/7/assets.json":
+			body := map[string]AssetUpload{}
+			json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			uploads[body["asset"].Key] = body["asset"].Value
+			mu.Unlock()
+			w.Write([]byte(`{"asset": {}}`))
+		case "DELETE /admin/themes/7/assets.json":
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "templates"), 0755)
+	os.WriteFile(filepath.Join(dir, "templates", "index.liquid"), []byte("index"), 0644)
+	os.WriteFile(filepath.Join(dir, "templates", "old.liquid"), []byte("old"), 0644)
+
+	ts := a.NewThemeSync(7, dir)
+	ts.Debounce = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan ThemeWatchEvent, 10)
+	done := make(chan error)
+	go func() {
+		done <- ts.Watch(ctx, func(e ThemeWatchEvent) { events <- e })
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	os.WriteFile(filepath.Join(dir, "templates", "index.liquid"), []byte("draft"), 0644)
+	os.WriteFile(filepath.Join(dir, "templates", "index.liquid"), []byte("final"), 0644)
+	os.WriteFile(filepath.Join(dir, "templates", ".index.liquid.swp"), []byte("swap"), 0644)
+	os.Remove(filepath.Join(dir, "templates", "old.liquid"))
+	os.MkdirAll(filepath.Join(dir, "snippets"), 0755)
+	os.WriteFile(filepath.Join(dir, "snippets", "new.liquid"), []byte("new"), 0644)
+
+	got := map[string]bool{}
+	for len(got) < 3 {
+		select {
+		case e := <-events:
+			if e.Err != nil {
+				t.Errorf("Unexpected error for %s: %v", e.Key, e.Err)
+			}
+			got[fmt.Sprintf("%s deleted=%v", e.Key, e.Deleted)] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for events, got %v", got)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"templates/index.liquid deleted=false", "templates/old.liquid deleted=true", "snippets/new.liquid deleted=false"} {
+		if !got[want] {
+			t.Errorf("Expected event %q, got %v", want, got)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(uploads) != "map[snippets/new.liquid:new templates/index.liquid:final]" {
+		t.Errorf("Expected the final contents to be uploaded, got %v", uploads)
+	}
+}
+
+func TestThemeWatchWithoutCallback(t *testing.T) {
+	uploaded := make(chan string, 10)
+	a, srv := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]AssetUpload{}
+		json.NewDecoder(r.Body).Decode(&body)
+		uploaded <- body["asset"].Key
+		w.Write([]byte(`{"asset": {}}`))
+	})
+	defer srv.Close()
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "templates"), 0755)
+
+	ts := a.NewThemeSync(7, dir)
+	ts.Debounce = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- ts.Watch(ctx, nil)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	os.WriteFile(filepath.Join(dir, "templates", "index.liquid"), []byte("index"), 0644)
+
+	select {
+	case key := <-uploaded:
+		if key != "templates/index.liquid" {
+			t.Errorf("Unexpected upload %s", key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the upload")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
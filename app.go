@@ -2,6 +2,7 @@ package shopify
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/subtle"
@@ -20,13 +21,27 @@ type App struct {
 	APISecret       string
 	RedirectURI     string
 	IgnoreSignature bool
+
+	clientConfig
+}
+
+// NewApp returns an App for the given API credentials.
+func NewApp(apiKey, apiSecret, redirectURI string, opts ...ClientOption) *App {
+	app := &App{APIKey: apiKey, APISecret: apiSecret, RedirectURI: redirectURI}
+	app.clientConfig.apply(opts)
+	return app
+}
+
+// Apply configures an existing App, e.g. one built as a struct literal.
+func (s *App) Apply(opts ...ClientOption) {
+	s.clientConfig.apply(opts)
 }
 
 func (s *App) AuthorizeURL(shop string, scopes string) string {
-	var u url.URL
-	u.Scheme = "https"
-	u.Host = shop
-	u.Path = "/admin/oauth/authorize"
+	u, err := url.Parse(s.shopURL(shop) + "/admin/oauth/authorize")
+	if err != nil {
+		return ""
+	}
 	q := u.Query()
 	q.Set("client_id", s.APIKey)
 	q.Set("scope", scopes)
@@ -131,7 +146,11 @@ func (s *App) signatureString(u *url.URL, prependSig bool) string {
 }
 
 func (s *App) AccessToken(shop string, code string) (string, error) {
-	url := fmt.Sprintf("https://%s/admin/oauth/access_token.json", shop)
+	return s.AccessTokenCtx(context.Background(), shop, code)
+}
+
+func (s *App) AccessTokenCtx(ctx context.Context, shop string, code string) (string, error) {
+	url := s.shopURL(shop) + "/admin/oauth/access_token.json"
 
 	data := map[string]string{
 		"client_id":     s.APIKey,
@@ -145,14 +164,14 @@ func (s *App) AccessToken(shop string, code string) (string, error) {
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", url, buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, buf)
 	if err != nil {
 		return "", err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	s.setHeaders(req)
 
-	transport := &http.Transport{}
-	response, err := transport.RoundTrip(req)
+	response, err := s.httpClient().Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -166,7 +185,12 @@ func (s *App) AccessToken(shop string, code string) (string, error) {
 	}
 
 	if _, ok := token["error"]; ok {
-		return "", fmt.Errorf("%s", token["error"])
+		return "", &ResponseError{
+			Status:   response.StatusCode,
+			Method:   "POST",
+			Endpoint: "/admin/oauth/access_token.json",
+			Message:  token["error"],
+		}
 	}
 
 	if _, ok := token["access_token"]; !ok {
@@ -1,6 +1,8 @@
 package shopify
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 )
@@ -57,3 +59,25 @@ func TestIgnoreSignature(t *testing.T) {
 		t.Errorf("IgnoreSignature didn't work for AppProxy")
 	}
 }
+
+func TestAccessTokenWithOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/oauth/access_token.json" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if ua := r.Header.Get("User-Agent"); ua != "test-agent" {
+			t.Errorf("Expected User-Agent test-agent, got %s", ua)
+		}
+		w.Write([]byte(`{"access_token": "secret-token"}`))
+	}))
+	defer srv.Close()
+
+	a := NewApp("asdf", "1234", "http://localhost:4000", WithBaseURL(srv.URL), WithUserAgent("test-agent"))
+	token, err := a.AccessToken("burnsmod.myshopify.com", "code")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if token != "secret-token" {
+		t.Errorf("Expected secret-token, got %s", token)
+	}
+}
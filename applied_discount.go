@@ -0,0 +1,22 @@
+package shopify
+
+// Value types of an AppliedDiscount.
+const (
+	DiscountFixedAmount = "fixed_amount"
+	DiscountPercentage  = "percentage"
+)
+
+// AppliedDiscount is a discount applied to a line item or a whole draft
+// order. Value is the amount or percentage off depending on ValueType, and
+// Amount the resulting discount, calculated by Shopify.
+type AppliedDiscount struct {
+	Amount Money `json:"amount,omitzero"`
+
+	Description string `json:"description,omitempty"`
+
+	Title string `json:"title,omitempty"`
+
+	Value string `json:"value,omitempty"`
+
+	ValueType string `json:"value_type,omitempty"`
+}
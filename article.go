@@ -3,11 +3,11 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
-
-	"time"
 )
 
 type Article struct {
@@ -17,11 +17,11 @@ type Article struct {
 
 	BodyHtml string `json:"body_html"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
 	Id int64 `json:"id"`
 
-	PublishedAt time.Time `json:"published_at"`
+	PublishedAt Timestamp `json:"published_at,omitzero"`
 
 	SummaryHtml string `json:"summary_html"`
 
@@ -29,7 +29,7 @@ type Article struct {
 
 	Title string `json:"title"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
 	UserId int64 `json:"user_id"`
 
@@ -39,14 +39,18 @@ type Article struct {
 }
 
 func (api *API) Articles() ([]Article, error) {
-	res, status, err := api.request("/admin/articles.json", "GET", nil, nil)
+	return api.ArticlesCtx(context.Background())
+}
+
+func (api *API) ArticlesCtx(ctx context.Context) ([]Article, error) {
+	res, status, err := api.requestContext(ctx, "/admin/articles.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Article{}
@@ -58,24 +62,35 @@ func (api *API) Articles() ([]Article, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
+// ArticlesIter walks every page of articles.
+func (api *API) ArticlesIter(ctx context.Context, options *ListOptions) *Iterator[Article] {
+	return newIterator(ctx, api, "/admin/articles.json", "articles", options,
+		func(v *Article) int64 { return v.Id },
+		func(v *Article) { v.api = api })
+}
+
 func (api *API) Article(id int64) (*Article, error) {
+	return api.ArticleCtx(context.Background(), id)
+}
+
+func (api *API) ArticleCtx(ctx context.Context, id int64) (*Article, error) {
 	endpoint := fmt.Sprintf("/admin/articles/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Article{}
@@ -97,9 +112,13 @@ func (api *API) NewArticle() *Article {
 }
 
 func (obj *Article) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Article) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/articles/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/articles.json")
@@ -117,20 +136,14 @@ func (obj *Article) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Article{}
@@ -140,7 +153,9 @@ func (obj *Article) Save() error {
 		return err
 	}
 
+	api := obj.api
 	*obj = r["article"]
+	obj.api = api
 
 	return nil
 }
@@ -1,247 +1,226 @@
 package shopify
 
-
 import (
-  
-    "bytes"
-  
-    "encoding/json"
-  
-    "fmt"
-  
-    "time"
-
-    "io/ioutil"
-  
-)
+	"bytes"
+
+	"context"
 
+	"encoding/json"
+
+	"fmt"
+
+	"net/url"
+)
 
 type Asset struct {
-  
-    Attachment string `json:"attachment,omitempty"`
-
-    ContentType string `json:"content_type,omitempty"`
-  
-    CreatedAt time.Time `json:"created_at,omitempty"`
-
-    Key string `json:"key,omitempty"`
-  
-    Size int64 `json:"size,omitempty"`
-  
-    SourceKey string `json:"source_key,omitempty"`
-  
-    Src string `json:"src,omitempty"`
-
-    ThemeId int64 `json:"theme_id,omitempty"`
-  
-    UpdatedAt time.Time `json:"updated_at,omitempty"`
-  
-    Value string `json:"value,omitempty"`
-  
-
-  
-    api *API
-  
-}
+	Attachment string `json:"attachment,omitempty"`
 
-type AssetUpload struct {
-  
-    Attachment string `json:"attachment,omitempty"`
+	// Checksum is the MD5 of the asset's contents, in hex.
+	Checksum string `json:"checksum,omitempty"`
 
-    Key string `json:"key,omitempty"`
-  
-    Value string `json:"value,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
 
-  
-    api *API
-  
-}
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
+	Key string `json:"key,omitempty"`
 
+	Size int64 `json:"size,omitempty"`
 
-func (api *API) Assets(themeId int64) ([]Asset, error) {
-  
-  endpoint := fmt.Sprintf("/admin/themes/%d/assets.json", themeId)
-  res, status, err := api.request(endpoint, "GET", nil, nil)
+	SourceKey string `json:"source_key,omitempty"`
+
+	Src string `json:"src,omitempty"`
+
+	ThemeId int64 `json:"theme_id,omitempty"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
-  if err != nil {
-    return nil, err
-  }
+	Value string `json:"value,omitempty"`
 
-  if status != 200 {
-    return nil, fmt.Errorf("Status returned: %d", status)
-  }
+	api *API
+}
 
-  r := &map[string][]Asset{}
-  err = json.NewDecoder(res).Decode(r)
+type AssetUpload struct {
+	Attachment string `json:"attachment,omitempty"`
 
-  result := (*r)["assets"]
+	Key string `json:"key,omitempty"`
 
-  if err != nil {
-    return nil, err
-  }
+	Value string `json:"value,omitempty"`
 
-  for _, v := range result {
-    v.api = api
-  }
+	api *API
+}
 
-  return result, nil
+func (api *API) Assets(themeId int64) ([]Asset, error) {
+	return api.AssetsCtx(context.Background(), themeId)
 }
 
+func (api *API) AssetsCtx(ctx context.Context, themeId int64) ([]Asset, error) {
 
+	endpoint := fmt.Sprintf("/admin/themes/%d/assets.json", themeId)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]Asset{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["assets"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = api
+	}
+
+	return result, nil
+}
 
 func (api *API) Asset(themeId int64, assetKey string) (*Asset, error) {
-  endpoint := fmt.Sprintf("/admin/themes/%d/assets.json?asset[key]=%s&theme_id=%d", themeId, assetKey, themeId )
+	return api.AssetCtx(context.Background(), themeId, assetKey)
+}
 
-  res, status, err := api.request(endpoint, "GET", nil, nil)
+func (api *API) AssetCtx(ctx context.Context, themeId int64, assetKey string) (*Asset, error) {
+	endpoint := fmt.Sprintf("/admin/themes/%d/assets.json?asset[key]=%s&theme_id=%d", themeId, url.QueryEscape(assetKey), themeId)
 
-  if err != nil {
-    return nil, err
-  }
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
-  if status != 200 {
-    return nil, fmt.Errorf("Status returned: %d", status)
-  }
+	if err != nil {
+		return nil, err
+	}
 
-  r := map[string]Asset{}
-  err = json.NewDecoder(res).Decode(&r)
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
 
-  result := r["asset"]
+	r := map[string]Asset{}
+	err = json.NewDecoder(res).Decode(&r)
 
-  if err != nil {
-    return nil, err
-  }
+	result := r["asset"]
 
-  result.api = api
+	if err != nil {
+		return nil, err
+	}
 
-  return &result, nil
-}
+	result.api = api
 
+	return &result, nil
+}
 
 func (api *API) NewAsset() *Asset {
-  return &Asset{api: api}
+	return &Asset{api: api}
 }
 
 func (api *API) NewAssetUpload() *AssetUpload {
-  return &AssetUpload{api: api}
+	return &AssetUpload{api: api}
 }
 
+func (obj *Asset) Save() error {
+	return obj.SaveCtx(context.Background())
+}
 
-func (obj *Asset) Save() (error) {
-  endpoint := fmt.Sprintf("/admin/themes/%d/assets.json", obj.ThemeId)
-  method := "PUT"
-  expectedStatus := 200
+func (obj *Asset) SaveCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/themes/%d/assets.json", obj.ThemeId)
+	method := "PUT"
+	expectedStatus := 200
 
-  body := map[string]*Asset{}
-  body["asset"] = obj
+	body := map[string]*Asset{}
+	body["asset"] = obj
 
-  buf := &bytes.Buffer{}
-  err := json.NewEncoder(buf).Encode(body)
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
 
-  if b, err := ioutil.ReadAll(buf); err == nil {
-      fmt.Printf(string(b))
-  }
+	if err != nil {
+		return err
+	}
 
-  if err != nil {
-    return err
-  }
-  
-  res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
-  if err != nil {
-    return err
-  }
+	if err != nil {
+		return err
+	}
 
-  if status != expectedStatus {
-    r := errorResponse{}
-    err = json.NewDecoder(res).Decode(&r)
-    if err == nil {
-      return fmt.Errorf("Status %d: %v", status, r.Errors)
-    } else {
-      return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-    }
-  }
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
 
-  r := map[string]Asset{}
-  err = json.NewDecoder(res).Decode(&r)
+	r := map[string]Asset{}
+	err = json.NewDecoder(res).Decode(&r)
 
-  if err != nil {
-    return err
-  }
+	if err != nil {
+		return err
+	}
 
-  *obj = r["asset"]
+	*obj = r["asset"]
 
-  return nil
+	return nil
 }
 
-func (obj *AssetUpload) Upload(themeId int64) (error) {
-  endpoint := fmt.Sprintf("/admin/themes/%d/assets.json", themeId)
-  method := "PUT"
-  expectedStatus := 200
-
-  body := map[string]*AssetUpload{}
-  body["asset"] = obj
-
-  buf := &bytes.Buffer{}
-  err := json.NewEncoder(buf).Encode(body)
-  
-  if err != nil {
-    return err
-  }
-  
-  res, status, err := obj.api.request(endpoint, method, nil, buf)
-
-  if err != nil {
-    return err
-  }
-
-  if status != expectedStatus {
-    r := errorResponse{}
-    err = json.NewDecoder(res).Decode(&r)
-    if err == nil {
-      return fmt.Errorf("Status %d: %v", status, r.Errors)
-    } else {
-      return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-    }
-  }
-
-  r := map[string]AssetUpload{}
-  err = json.NewDecoder(res).Decode(&r)
-
-  if err != nil {
-    return err
-  }
-
-  *obj = r["asset"]
-
-  return nil
+func (obj *AssetUpload) Upload(themeId int64) error {
+	return obj.UploadCtx(context.Background(), themeId)
 }
 
+func (obj *AssetUpload) UploadCtx(ctx context.Context, themeId int64) error {
+	endpoint := fmt.Sprintf("/admin/themes/%d/assets.json", themeId)
+	method := "PUT"
+	expectedStatus := 200
+
+	body := map[string]*AssetUpload{}
+	body["asset"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
 
-func (api *API) Delete(themeId int64, assetKey string) (error) {
-  endpoint := fmt.Sprintf("/admin/themes/%d/assets.json?asset[key]=%s", themeId, assetKey )
+	if err != nil {
+		return err
+	}
 
-  res, status, err := api.request(endpoint, "DELETE", nil, nil)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
-  _ = res
+	if err != nil {
+		return err
+	}
 
-  if err != nil {
-    return err
-  }
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
 
-  if status == 403{
-    return fmt.Errorf("Shopify does not allow this asset to be deleted.")    
-  }
+	r := map[string]AssetUpload{}
+	err = json.NewDecoder(res).Decode(&r)
 
-  if status != 200 {
-    return fmt.Errorf("Status returned: %d", status)
-  }
+	if err != nil {
+		return err
+	}
 
-  return nil
+	*obj = r["asset"]
+
+	return nil
+}
+
+func (api *API) Delete(themeId int64, assetKey string) error {
+	return api.DeleteCtx(context.Background(), themeId, assetKey)
 }
 
+func (api *API) DeleteCtx(ctx context.Context, themeId int64, assetKey string) error {
+	endpoint := fmt.Sprintf("/admin/themes/%d/assets.json?asset[key]=%s", themeId, url.QueryEscape(assetKey))
 
+	res, status, err := api.requestContext(ctx, endpoint, "DELETE", nil, nil)
 
+	// Shopify refuses to delete assets the theme depends on with a 403,
+	// which surfaces as ErrForbidden.
+	if err != nil {
+		return err
+	}
 
+	if status != 200 {
+		return newResponseError(status, res)
+	}
 
+	return nil
+}
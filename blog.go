@@ -3,17 +3,17 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
-
-	"time"
 )
 
 type Blog struct {
 	Commentable string `json:"commentable"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
 	Feedburner string `json:"feedburner"`
 
@@ -27,7 +27,7 @@ type Blog struct {
 
 	Title string `json:"title"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
 	Tags string `json:"tags"`
 
@@ -35,14 +35,18 @@ type Blog struct {
 }
 
 func (api *API) Blogs() ([]Blog, error) {
-	res, status, err := api.request("/admin/blogs.json", "GET", nil, nil)
+	return api.BlogsCtx(context.Background())
+}
+
+func (api *API) BlogsCtx(ctx context.Context) ([]Blog, error) {
+	res, status, err := api.requestContext(ctx, "/admin/blogs.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Blog{}
@@ -54,24 +58,35 @@ func (api *API) Blogs() ([]Blog, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
+// BlogsIter walks every page of blogs.
+func (api *API) BlogsIter(ctx context.Context, options *ListOptions) *Iterator[Blog] {
+	return newIterator(ctx, api, "/admin/blogs.json", "blogs", options,
+		func(v *Blog) int64 { return v.Id },
+		func(v *Blog) { v.api = api })
+}
+
 func (api *API) Blog(id int64) (*Blog, error) {
+	return api.BlogCtx(context.Background(), id)
+}
+
+func (api *API) BlogCtx(ctx context.Context, id int64) (*Blog, error) {
 	endpoint := fmt.Sprintf("/admin/blogs/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Blog{}
@@ -93,9 +108,13 @@ func (api *API) NewBlog() *Blog {
 }
 
 func (obj *Blog) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Blog) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/blogs/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/blogs.json")
@@ -113,20 +132,14 @@ func (obj *Blog) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Blog{}
@@ -136,7 +149,9 @@ func (obj *Blog) Save() error {
 		return err
 	}
 
+	api := obj.api
 	*obj = r["blog"]
+	obj.api = api
 
 	return nil
 }
@@ -0,0 +1,374 @@
+package shopify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Terminal and in-flight states of a BulkOperation.
+const (
+	BulkOperationCreated   = "CREATED"
+	BulkOperationRunning   = "RUNNING"
+	BulkOperationCompleted = "COMPLETED"
+	BulkOperationCanceling = "CANCELING"
+	BulkOperationCanceled  = "CANCELED"
+	BulkOperationFailed    = "FAILED"
+	BulkOperationExpired   = "EXPIRED"
+)
+
+// BULK_POLL_INTERVAL is how often BulkOperation.Wait polls by default.
+const BULK_POLL_INTERVAL = 5 * time.Second
+
+// BulkOperation is a GraphQL bulk query running on Shopify's side. Once
+// completed its results are a JSONL file at URL, one object per line, with
+// nested connections flattened into their own lines pointing back at their
+// parent through __parentId.
+type BulkOperation struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	ErrorCode      string `json:"errorCode"`
+	ObjectCount    string `json:"objectCount"`
+	URL            string `json:"url"`
+	PartialDataURL string `json:"partialDataUrl"`
+
+	api *API
+}
+
+// BulkOperationError is returned when a bulk operation ends in any state
+// other than COMPLETED.
+type BulkOperationError struct {
+	ID        string
+	Status    string
+	ErrorCode string
+}
+
+func (e *BulkOperationError) Error() string {
+	if e.ErrorCode != "" {
+		return fmt.Sprintf("shopify: bulk operation %s %s: %s", e.ID, e.Status, e.ErrorCode)
+	}
+	return fmt.Sprintf("shopify: bulk operation %s %s", e.ID, e.Status)
+}
+
+// BulkHandler receives the records of a bulk operation's results. Products,
+// variants and orders are converted into the REST structs of this package
+// when their callback is set; every other record goes to Record.
+type BulkHandler struct {
+	Product func(*Product) error
+	Variant func(*Variant) error
+	Order   func(*Order) error
+	Record  func(*BulkRecord) error
+}
+
+// BulkRecord is one line of a bulk operation's results.
+type BulkRecord struct {
+	ID       string // e.g. gid://shopify/Product/123, empty for objects without an id
+	ParentID string // __parentId of records from a nested connection
+	Raw      json.RawMessage
+}
+
+const bulkOperationFields = `id status errorCode objectCount url partialDataUrl`
+
+// BulkQuery submits query through bulkOperationRunQuery. Shopify runs a
+// single bulk operation per shop at a time.
+func (api *API) BulkQuery(ctx context.Context, query string) (*BulkOperation, error) {
+	mutation := `mutation bulkOperationRunQuery($query: String!) {
+		bulkOperationRunQuery(query: $query) {
+			bulkOperation { ` + bulkOperationFields + ` }
+			userErrors { field message }
+		}
+	}`
+
+	r := struct {
+		BulkOperationRunQuery struct {
+			BulkOperation *BulkOperation    `json:"bulkOperation"`
+			UserErrors    GraphQLUserErrors `json:"userErrors"`
+		} `json:"bulkOperationRunQuery"`
+	}{}
+	if _, err := api.GraphQL(ctx, mutation, map[string]interface{}{"query": query}, &r); err != nil {
+		return nil, err
+	}
+
+	result := r.BulkOperationRunQuery
+	if len(result.UserErrors) > 0 {
+		return nil, result.UserErrors
+	}
+	if result.BulkOperation == nil {
+		return nil, fmt.Errorf("shopify: bulkOperationRunQuery returned no operation")
+	}
+
+	result.BulkOperation.api = api
+	return result.BulkOperation, nil
+}
+
+// CurrentBulkOperation returns the shop's most recent bulk query, or nil if
+// there is none.
+func (api *API) CurrentBulkOperation(ctx context.Context) (*BulkOperation, error) {
+	r := struct {
+		CurrentBulkOperation *BulkOperation `json:"currentBulkOperation"`
+	}{}
+	query := `{ currentBulkOperation { ` + bulkOperationFields + ` } }`
+	if _, err := api.GraphQL(ctx, query, nil, &r); err != nil {
+		return nil, err
+	}
+
+	if r.CurrentBulkOperation != nil {
+		r.CurrentBulkOperation.api = api
+	}
+	return r.CurrentBulkOperation, nil
+}
+
+// RunBulkQuery submits query, waits for it to complete and streams its
+// results into handler.
+func (api *API) RunBulkQuery(ctx context.Context, query string, handler BulkHandler) error {
+	op, err := api.BulkQuery(ctx, query)
+	if err != nil {
+		return err
+	}
+	if err = op.Wait(ctx, BULK_POLL_INTERVAL); err != nil {
+		return err
+	}
+	return op.Stream(ctx, handler)
+}
+
+// Refresh reloads the operation's status.
+func (obj *BulkOperation) Refresh(ctx context.Context) error {
+	r := struct {
+		Node *BulkOperation `json:"node"`
+	}{}
+	query := `query bulkOperation($id: ID!) { node(id: $id) { ... on BulkOperation { ` + bulkOperationFields + ` } } }`
+	if _, err := obj.api.GraphQL(ctx, query, map[string]interface{}{"id": obj.ID}, &r); err != nil {
+		return err
+	}
+	if r.Node == nil {
+		return fmt.Errorf("shopify: bulk operation %s not found", obj.ID)
+	}
+
+	api := obj.api
+	*obj = *r.Node
+	obj.api = api
+
+	return nil
+}
+
+// Done reports whether the operation reached a final state.
+func (obj *BulkOperation) Done() bool {
+	switch obj.Status {
+	case BulkOperationCompleted, BulkOperationCanceled, BulkOperationFailed, BulkOperationExpired:
+		return true
+	}
+	return false
+}
+
+// Wait polls the operation every interval until it finishes. It returns a
+// *BulkOperationError unless the operation completed.
+func (obj *BulkOperation) Wait(ctx context.Context, interval time.Duration) error {
+	for !obj.Done() {
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+		if err := obj.Refresh(ctx); err != nil {
+			return err
+		}
+	}
+
+	if obj.Status != BulkOperationCompleted {
+		return &BulkOperationError{ID: obj.ID, Status: obj.Status, ErrorCode: obj.ErrorCode}
+	}
+	return nil
+}
+
+// Stream downloads the results of a completed operation and decodes them line
+// by line into handler, so the whole file never has to fit in memory.
+// Returning an error from a callback stops the stream with that error.
+func (obj *BulkOperation) Stream(ctx context.Context, handler BulkHandler) error {
+	if obj.URL == "" {
+		// completed without matching any object
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", obj.URL, nil)
+	if err != nil {
+		return err
+	}
+	obj.api.setHeaders(req)
+
+	resp, err := obj.api.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return &ResponseError{Status: resp.StatusCode, Method: "GET", Endpoint: obj.URL}
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		rec := &BulkRecord{}
+		if err = dec.Decode(&rec.Raw); err != nil {
+			return err
+		}
+
+		ids := struct {
+			ID       string `json:"id"`
+			ParentID string `json:"__parentId"`
+		}{}
+		if err = json.Unmarshal(rec.Raw, &ids); err != nil {
+			return err
+		}
+		rec.ID = ids.ID
+		rec.ParentID = ids.ParentID
+
+		if err = handler.dispatch(obj.api, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h BulkHandler) dispatch(api *API, rec *BulkRecord) error {
+	switch {
+	case rec.Type() == "Product" && h.Product != nil:
+		v := &Product{}
+		if err := rec.decodeREST(v); err != nil {
+			return err
+		}
+		v.bind(api)
+		return h.Product(v)
+	case rec.Type() == "ProductVariant" && h.Variant != nil:
+		v := &Variant{}
+		if err := rec.decodeREST(v); err != nil {
+			return err
+		}
+		if gidType(rec.ParentID) == "Product" {
+			v.ProductId = legacyID(rec.ParentID)
+		}
+		v.bind(api)
+		return h.Variant(v)
+	case rec.Type() == "Order" && h.Order != nil:
+		v := &Order{}
+		if err := rec.decodeREST(v); err != nil {
+			return err
+		}
+		v.bind(api)
+		return h.Order(v)
+	case h.Record != nil:
+		return h.Record(rec)
+	}
+	return nil
+}
+
+// Type returns the GraphQL type of the record taken from its id, e.g.
+// "Product" or "ProductVariant".
+func (rec *BulkRecord) Type() string {
+	return gidType(rec.ID)
+}
+
+// LegacyID returns the numeric id the REST API uses for the record.
+func (rec *BulkRecord) LegacyID() int64 {
+	return legacyID(rec.ID)
+}
+
+// Decode unmarshals the record as returned by GraphQL.
+func (rec *BulkRecord) Decode(v interface{}) error {
+	return json.Unmarshal(rec.Raw, v)
+}
+
+// bulkFieldNames maps GraphQL field names to REST ones where they differ by
+// more than their casing.
+var bulkFieldNames = map[string]string{
+	"descriptionHtml": "body_html",
+}
+
+// decodeREST unmarshals the record into one of the REST structs by renaming
+// camelCase fields to snake_case, turning gid:// ids into numeric ones and
+// joining tag lists the way the REST API returns them.
+func (rec *BulkRecord) decodeREST(v interface{}) error {
+	var raw interface{}
+	if err := json.Unmarshal(rec.Raw, &raw); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(restShape(raw))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func restShape(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, val := range v {
+			if k == "__parentId" {
+				continue
+			}
+			name, ok := bulkFieldNames[k]
+			if !ok {
+				name = snakeCase(k)
+			}
+			switch {
+			case name == "id":
+				if s, ok := val.(string); ok {
+					if n := legacyID(s); n != 0 {
+						val = n
+					}
+				}
+			case name == "tags":
+				if tags, ok := val.([]interface{}); ok {
+					val = strings.Join(stringsOf(tags), ", ")
+				}
+			default:
+				val = restShape(val)
+			}
+			m[name] = val
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = restShape(v[i])
+		}
+		return v
+	}
+	return v
+}
+
+func snakeCase(s string) string {
+	b := strings.Builder{}
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// gidType returns the type part of a gid such as gid://shopify/Product/1.
+func gidType(gid string) string {
+	parts := strings.Split(strings.TrimPrefix(gid, "gid://shopify/"), "/")
+	if len(parts) != 2 {
+		return ""
+	}
+	return parts[0]
+}
+
+// legacyID returns the numeric part of a gid such as gid://shopify/Product/1.
+func legacyID(gid string) int64 {
+	i := strings.LastIndex(gid, "/")
+	if i < 0 {
+		return 0
+	}
+	id, _ := strconv.ParseInt(strings.SplitN(gid[i+1:], "?", 2)[0], 10, 64)
+	return id
+}
@@ -1,11 +1,9 @@
 package shopify
 
 import (
-	"encoding/json"
-
-	"fmt"
+	"context"
 
-	"time"
+	"encoding/json"
 )
 
 type Checkout struct {
@@ -13,11 +11,11 @@ type Checkout struct {
 
 	CartToken string `json:"cart_token"`
 
-	ClosedAt string `json:"closed_at"`
+	ClosedAt Timestamp `json:"closed_at,omitzero"`
 
-	CompletedAt string `json:"completed_at"`
+	CompletedAt Timestamp `json:"completed_at,omitzero"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
 	Currency string `json:"currency"`
 
@@ -41,23 +39,23 @@ type Checkout struct {
 
 	SourceUrl string `json:"source_url"`
 
-	SubtotalPrice time.Time `json:"subtotal_price"`
+	SubtotalPrice Money `json:"subtotal_price,omitzero"`
 
 	TaxesIncluded bool `json:"taxes_included"`
 
-	Token time.Time `json:"token"`
+	Token string `json:"token"`
 
-	TotalDiscounts string `json:"total_discounts"`
+	TotalDiscounts Money `json:"total_discounts,omitzero"`
 
-	TotalLineItemsPrice time.Time `json:"total_line_items_price"`
+	TotalLineItemsPrice Money `json:"total_line_items_price,omitzero"`
 
-	TotalPrice time.Time `json:"total_price"`
+	TotalPrice Money `json:"total_price,omitzero"`
 
-	TotalTax string `json:"total_tax"`
+	TotalTax Money `json:"total_tax,omitzero"`
 
 	TotalWeight int64 `json:"total_weight"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
 	LineItems []LineItem `json:"line_items"`
 
@@ -67,7 +65,7 @@ type Checkout struct {
 
 	Source string `json:"source"`
 
-	DiscountCodes []interface{} `json:"discount_codes"`
+	DiscountCodes []OrderDiscountCode `json:"discount_codes"`
 
 	AbandonedCheckoutUrl string `json:"abandoned_checkout_url"`
 
@@ -83,14 +81,18 @@ type Checkout struct {
 }
 
 func (api *API) Checkouts() ([]Checkout, error) {
-	res, status, err := api.request("/admin/checkouts.json", "GET", nil, nil)
+	return api.CheckoutsCtx(context.Background())
+}
+
+func (api *API) CheckoutsCtx(ctx context.Context) ([]Checkout, error) {
+	res, status, err := api.requestContext(ctx, "/admin/checkouts.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Checkout{}
@@ -102,9 +104,16 @@ func (api *API) Checkouts() ([]Checkout, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
+
+// CheckoutsIter walks every page of checkouts.
+func (api *API) CheckoutsIter(ctx context.Context, options *ListOptions) *Iterator[Checkout] {
+	return newIterator(ctx, api, "/admin/checkouts.json", "checkouts", options,
+		func(v *Checkout) int64 { return v.Id },
+		func(v *Checkout) { v.api = api })
+}
@@ -1,46 +1,64 @@
 package shopify
 
 import (
+	"bytes"
+
+	"context"
+
 	"encoding/json"
 
 	"fmt"
-
-	"time"
 )
 
+// Collect puts a product in a custom collection.
 type Collect struct {
-	CollectionId int64 `json:"collection_id"`
+	CollectionId int64 `json:"collection_id,omitempty"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
-	Featured bool `json:"featured"`
+	Featured bool `json:"featured,omitempty"`
 
-	Id int64 `json:"id"`
+	Id int64 `json:"id,omitempty"`
 
-	Position int64 `json:"position"`
+	Position int64 `json:"position,omitempty"`
 
-	ProductId int64 `json:"product_id"`
+	ProductId int64 `json:"product_id,omitempty"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
-	SortValue string `json:"sort_value"`
+	SortValue string `json:"sort_value,omitempty"`
 
 	api *API
 }
 
+type CollectsOptions struct {
+	ProductID    int64  `url:"product_id,omitempty"`
+	CollectionID int64  `url:"collection_id,omitempty"`
+	Limit        int    `url:"limit,omitempty"`
+	Page         int    `url:"page,omitempty"`
+	SinceID      int64  `url:"since_id,omitempty"`
+	Fields       string `url:"fields,omitempty"`
+}
+
 func (api *API) NewCollect() *Collect {
 	return &Collect{api: api}
 }
 
-func (api *API) Collects() ([]Collect, error) {
-	res, status, err := api.request("/admin/collects.json", "GET", nil, nil)
+func (api *API) Collects(options *CollectsOptions) ([]Collect, error) {
+	return api.CollectsCtx(context.Background(), options)
+}
+
+func (api *API) CollectsCtx(ctx context.Context, options *CollectsOptions) ([]Collect, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/collects.json?%v", qs)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Collect{}
@@ -51,24 +69,35 @@ func (api *API) Collects() ([]Collect, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
+// CollectsIter walks every page of collects.
+func (api *API) CollectsIter(ctx context.Context, options *CollectsOptions) *Iterator[Collect] {
+	return newIterator(ctx, api, "/admin/collects.json", "collects", options,
+		func(v *Collect) int64 { return v.Id },
+		func(v *Collect) { v.api = api })
+}
+
 func (api *API) Collect(id int64) (*Collect, error) {
+	return api.CollectCtx(context.Background(), id)
+}
+
+func (api *API) CollectCtx(ctx context.Context, id int64) (*Collect, error) {
 	endpoint := fmt.Sprintf("/admin/collects/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Collect{}
@@ -83,3 +112,73 @@ func (api *API) Collect(id int64) (*Collect, error) {
 
 	return &result, nil
 }
+
+// Save adds the product to the collection. Collects can't be changed once
+// created; delete and recreate them instead.
+func (obj *Collect) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Collect) SaveCtx(ctx context.Context) error {
+	if obj.Id != 0 {
+		return fmt.Errorf("shopify: collect %d already exists and can't be updated", obj.Id)
+	}
+
+	endpoint := "/admin/collects.json"
+
+	body := map[string]*Collect{}
+	body["collect"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != 201 {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]Collect{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["collect"]
+	obj.api = api
+
+	return nil
+}
+
+// Delete removes the product from the collection.
+func (obj *Collect) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *Collect) DeleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/collects/%d.json", obj.Id)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
@@ -31,14 +33,18 @@ type Country struct {
 }
 
 func (api *API) Countries() ([]Country, error) {
-	res, status, err := api.request("/admin/countries.json", "GET", nil, nil)
+	return api.CountriesCtx(context.Background())
+}
+
+func (api *API) CountriesCtx(ctx context.Context) ([]Country, error) {
+	res, status, err := api.requestContext(ctx, "/admin/countries.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Country{}
@@ -50,24 +56,28 @@ func (api *API) Countries() ([]Country, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
 func (api *API) Country(id int64) (*Country, error) {
+	return api.CountryCtx(context.Background(), id)
+}
+
+func (api *API) CountryCtx(ctx context.Context, id int64) (*Country, error) {
 	endpoint := fmt.Sprintf("/admin/countries/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Country{}
@@ -89,9 +99,13 @@ func (api *API) NewCountry() *Country {
 }
 
 func (obj *Country) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Country) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/countries/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/countries.json")
@@ -109,20 +123,14 @@ func (obj *Country) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Country{}
@@ -132,7 +140,9 @@ func (obj *Country) Save() error {
 		return err
 	}
 
+	api := obj.api
 	*obj = r["country"]
+	obj.api = api
 
 	return nil
 }
@@ -3,11 +3,25 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
 
-	"time"
+	"slices"
+)
+
+// Values of CustomCollection.SortOrder and SmartCollection.SortOrder.
+const (
+	CollectionSortAlphaAsc    = "alpha-asc"
+	CollectionSortAlphaDesc   = "alpha-desc"
+	CollectionSortBestSelling = "best-selling"
+	CollectionSortCreated     = "created"
+	CollectionSortCreatedDesc = "created-desc"
+	CollectionSortManual      = "manual"
+	CollectionSortPriceAsc    = "price-asc"
+	CollectionSortPriceDesc   = "price-desc"
 )
 
 type CustomCollection struct {
@@ -17,7 +31,7 @@ type CustomCollection struct {
 
 	Id int64 `json:"id"`
 
-	PublishedAt time.Time `json:"published_at"`
+	PublishedAt Timestamp `json:"published_at,omitzero"`
 
 	PublishedScope string `json:"published_scope"`
 
@@ -27,20 +41,24 @@ type CustomCollection struct {
 
 	Title string `json:"title"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
 	api *API
 }
 
 func (api *API) CustomCollections() ([]CustomCollection, error) {
-	res, status, err := api.request("/admin/custom_collections.json", "GET", nil, nil)
+	return api.CustomCollectionsCtx(context.Background())
+}
+
+func (api *API) CustomCollectionsCtx(ctx context.Context) ([]CustomCollection, error) {
+	res, status, err := api.requestContext(ctx, "/admin/custom_collections.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]CustomCollection{}
@@ -52,24 +70,35 @@ func (api *API) CustomCollections() ([]CustomCollection, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
+// CustomCollectionsIter walks every page of custom_collections.
+func (api *API) CustomCollectionsIter(ctx context.Context, options *ListOptions) *Iterator[CustomCollection] {
+	return newIterator(ctx, api, "/admin/custom_collections.json", "custom_collections", options,
+		func(v *CustomCollection) int64 { return v.Id },
+		func(v *CustomCollection) { v.api = api })
+}
+
 func (api *API) CustomCollection(id int64) (*CustomCollection, error) {
+	return api.CustomCollectionCtx(context.Background(), id)
+}
+
+func (api *API) CustomCollectionCtx(ctx context.Context, id int64) (*CustomCollection, error) {
 	endpoint := fmt.Sprintf("/admin/custom_collections/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]CustomCollection{}
@@ -91,9 +120,13 @@ func (api *API) NewCustomCollection() *CustomCollection {
 }
 
 func (obj *CustomCollection) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *CustomCollection) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/custom_collections/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/custom_collections.json")
@@ -111,20 +144,21 @@ func (obj *CustomCollection) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	return obj.request(ctx, endpoint, method, expectedStatus, buf)
+}
+
+// request sends body to endpoint and reloads the collection from the
+// response.
+func (obj *CustomCollection) request(ctx context.Context, endpoint, method string, expectedStatus int, body *bytes.Buffer) error {
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, method, nil, body)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]CustomCollection{}
@@ -135,6 +169,172 @@ func (obj *CustomCollection) Save() error {
 	}
 
 	*obj = r["custom_collection"]
+	obj.api = api
 
 	return nil
 }
+
+// Collects lists the collection's collects, which hold each product's
+// position when it is sorted manually.
+func (obj *CustomCollection) Collects() ([]Collect, error) {
+	return obj.CollectsCtx(context.Background())
+}
+
+func (obj *CustomCollection) CollectsCtx(ctx context.Context) ([]Collect, error) {
+	result := []Collect{}
+
+	it := obj.api.CollectsIter(ctx, &CollectsOptions{CollectionID: obj.Id, Limit: 250})
+	for it.Next() {
+		result = append(result, *it.Value())
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Products lists the products in the collection.
+func (obj *CustomCollection) Products(options *ProductsOptions) ([]*Product, error) {
+	return obj.ProductsCtx(context.Background(), options)
+}
+
+func (obj *CustomCollection) ProductsCtx(ctx context.Context, options *ProductsOptions) ([]*Product, error) {
+	o := ProductsOptions{}
+	if options != nil {
+		o = *options
+	}
+	o.CollectionID = fmt.Sprintf("%d", obj.Id)
+	return obj.api.ProductsCtx(ctx, &o)
+}
+
+// AddProducts adds the products to the collection, skipping those already
+// in it, and returns the collects it created.
+func (obj *CustomCollection) AddProducts(productIds ...int64) ([]Collect, error) {
+	return obj.AddProductsCtx(context.Background(), productIds...)
+}
+
+func (obj *CustomCollection) AddProductsCtx(ctx context.Context, productIds ...int64) ([]Collect, error) {
+	existing, err := obj.CollectsCtx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	member := map[int64]bool{}
+	for _, c := range existing {
+		member[c.ProductId] = true
+	}
+
+	result := []Collect{}
+	for _, id := range productIds {
+		if member[id] {
+			continue
+		}
+
+		c := &Collect{CollectionId: obj.Id, ProductId: id, api: obj.api}
+		if err = c.SaveCtx(ctx); err != nil {
+			return result, fmt.Errorf("shopify: adding product %d to collection %d: %w", id, obj.Id, err)
+		}
+
+		member[id] = true
+		result = append(result, *c)
+	}
+
+	return result, nil
+}
+
+// RemoveProducts takes the products out of the collection. Products that
+// aren't in it are ignored.
+func (obj *CustomCollection) RemoveProducts(productIds ...int64) error {
+	return obj.RemoveProductsCtx(context.Background(), productIds...)
+}
+
+func (obj *CustomCollection) RemoveProductsCtx(ctx context.Context, productIds ...int64) error {
+	existing, err := obj.CollectsCtx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, c := range existing {
+		if !slices.Contains(productIds, c.ProductId) {
+			continue
+		}
+
+		if err = c.DeleteCtx(ctx); err != nil {
+			return fmt.Errorf("shopify: removing product %d from collection %d: %w", c.ProductId, obj.Id, err)
+		}
+	}
+
+	return nil
+}
+
+// Reorder switches the collection to manual sorting and puts productIds
+// first, in that order. Products left out keep their relative order after
+// them. It fails without changing anything if a product isn't in the
+// collection.
+func (obj *CustomCollection) Reorder(productIds ...int64) error {
+	return obj.ReorderCtx(context.Background(), productIds...)
+}
+
+func (obj *CustomCollection) ReorderCtx(ctx context.Context, productIds ...int64) error {
+	existing, err := obj.CollectsCtx(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	slices.SortStableFunc(existing, func(a, b Collect) int {
+		return int(a.Position - b.Position)
+	})
+
+	members := map[int64]bool{}
+	for _, c := range existing {
+		members[c.ProductId] = true
+	}
+
+	order := []int64{}
+	for _, id := range productIds {
+		if !members[id] {
+			return fmt.Errorf("shopify: product %d is not in collection %d", id, obj.Id)
+		}
+		if !slices.Contains(order, id) {
+			order = append(order, id)
+		}
+	}
+	for _, c := range existing {
+		if !slices.Contains(order, c.ProductId) {
+			order = append(order, c.ProductId)
+		}
+	}
+
+	endpoint := fmt.Sprintf("/admin/custom_collections/%d.json", obj.Id)
+
+	type position struct {
+		ProductId int64 `json:"product_id"`
+		Position  int   `json:"position"`
+	}
+
+	collects := []position{}
+	for i, id := range order {
+		collects = append(collects, position{ProductId: id, Position: i + 1})
+	}
+
+	body := map[string]interface{}{}
+	body["custom_collection"] = map[string]interface{}{
+		"id":         obj.Id,
+		"sort_order": CollectionSortManual,
+		"collects":   collects,
+	}
+
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	return obj.request(ctx, endpoint, "PUT", 200, buf)
+}
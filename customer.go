@@ -3,6 +3,8 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
@@ -13,7 +15,7 @@ import (
 type Customer struct {
 	AcceptsMarketing bool `json:"accepts_marketing"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
 	Email string `json:"email"`
 
@@ -33,9 +35,9 @@ type Customer struct {
 
 	State string `json:"state"`
 
-	TotalSpent string `json:"total_spent"`
+	TotalSpent Money `json:"total_spent,omitzero"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
 	VerifiedEmail bool `json:"verified_email"`
 
@@ -43,22 +45,77 @@ type Customer struct {
 
 	LastOrderName string `json:"last_order_name"`
 
-	DefaultAddress DefaultAddress `json:"default_address"`
+	DefaultAddress CustomerAddress `json:"default_address"`
 
-	Addresses []DefaultAddress `json:"addresses"`
+	Addresses []CustomerAddress `json:"addresses"`
 
 	api *API
 }
 
-func (api *API) Customers() ([]Customer, error) {
-	res, status, err := api.request("/admin/customers.json", "GET", nil, nil)
+type CustomersOptions struct {
+	IDs          string    `url:"ids,omitempty"`
+	Limit        int       `url:"limit,omitempty"`
+	Page         int       `url:"page,omitempty"`
+	SinceID      int64     `url:"since_id,omitempty"`
+	CreatedAtMin time.Time `url:"created_at_min,omitempty"`
+	CreatedAtMax time.Time `url:"created_at_max,omitempty"`
+	UpdatedAtMin time.Time `url:"updated_at_min,omitempty"`
+	UpdatedAtMax time.Time `url:"updated_at_max,omitempty"`
+	Fields       string    `url:"fields,omitempty"`
+}
+
+func (api *API) Customers(options *CustomersOptions) ([]Customer, error) {
+	return api.CustomersCtx(context.Background(), options)
+}
+
+func (api *API) CustomersCtx(ctx context.Context, options *CustomersOptions) ([]Customer, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/customers.json?%v", qs)
+	return api.customers(ctx, endpoint)
+}
+
+// CustomersIter walks every page of customers.
+func (api *API) CustomersIter(ctx context.Context, options *CustomersOptions) *Iterator[Customer] {
+	return newIterator(ctx, api, "/admin/customers.json", "customers", options,
+		func(v *Customer) int64 { return v.Id },
+		func(v *Customer) { v.bind(api) })
+}
+
+type customerSearchOptions struct {
+	Query string `url:"query"`
+	Order string `url:"order,omitempty"`
+	Limit int    `url:"limit,omitempty"`
+}
+
+// SearchCustomers returns the customers matching query, written in the
+// customer search syntax of the admin, e.g. `email:bob@example.com` or
+// `country:Canada orders_count:>2`.
+func (api *API) SearchCustomers(query string) ([]Customer, error) {
+	return api.SearchCustomersCtx(context.Background(), query)
+}
+
+func (api *API) SearchCustomersCtx(ctx context.Context, query string) ([]Customer, error) {
+	qs := encodeOptions(&customerSearchOptions{Query: query})
+	endpoint := fmt.Sprintf("/admin/customers/search.json?%v", qs)
+	return api.customers(ctx, endpoint)
+}
+
+// SearchCustomersIter walks every page of the customers matching query.
+func (api *API) SearchCustomersIter(ctx context.Context, query string) *Iterator[Customer] {
+	return newIterator(ctx, api, "/admin/customers/search.json", "customers", &customerSearchOptions{Query: query, Limit: 250},
+		nil,
+		func(v *Customer) { v.bind(api) })
+}
+
+func (api *API) customers(ctx context.Context, endpoint string) ([]Customer, error) {
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Customer{}
@@ -70,24 +127,28 @@ func (api *API) Customers() ([]Customer, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].bind(api)
 	}
 
 	return result, nil
 }
 
 func (api *API) Customer(id int64) (*Customer, error) {
+	return api.CustomerCtx(context.Background(), id)
+}
+
+func (api *API) CustomerCtx(ctx context.Context, id int64) (*Customer, error) {
 	endpoint := fmt.Sprintf("/admin/customers/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Customer{}
@@ -99,7 +160,7 @@ func (api *API) Customer(id int64) (*Customer, error) {
 		return nil, err
 	}
 
-	result.api = api
+	result.bind(api)
 
 	return &result, nil
 }
@@ -108,10 +169,23 @@ func (api *API) NewCustomer() *Customer {
 	return &Customer{api: api}
 }
 
+// bind attaches api to the customer and its addresses.
+func (obj *Customer) bind(api *API) {
+	obj.api = api
+	obj.DefaultAddress.api = api
+	for i := range obj.Addresses {
+		obj.Addresses[i].api = api
+	}
+}
+
 func (obj *Customer) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Customer) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/customers/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/customers.json")
@@ -129,20 +203,15 @@ func (obj *Customer) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Customer{}
@@ -153,6 +222,138 @@ func (obj *Customer) Save() error {
 	}
 
 	*obj = r["customer"]
+	obj.bind(api)
+
+	return nil
+}
+
+func (obj *Customer) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *Customer) DeleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/customers/%d.json", obj.Id)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	return nil
+}
+
+// Orders lists the customer's orders. Like api.Orders, only open orders are
+// returned unless options.Status says otherwise.
+func (obj *Customer) Orders(options *OrdersOptions) ([]Order, error) {
+	return obj.OrdersCtx(context.Background(), options)
+}
+
+func (obj *Customer) OrdersCtx(ctx context.Context, options *OrdersOptions) ([]Order, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/customers/%d/orders.json?%v", obj.Id, qs)
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]Order{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["orders"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].bind(obj.api)
+	}
+
+	return result, nil
+}
+
+// CustomerInvite customizes the account invite email. Empty fields use the
+// shop's defaults.
+type CustomerInvite struct {
+	To            string   `json:"to,omitempty"`
+	From          string   `json:"from,omitempty"`
+	Bcc           []string `json:"bcc,omitempty"`
+	Subject       string   `json:"subject,omitempty"`
+	CustomMessage string   `json:"custom_message,omitempty"`
+}
+
+// SendInvite emails the customer an invite to create their account. invite
+// may be nil.
+func (obj *Customer) SendInvite(invite *CustomerInvite) error {
+	return obj.SendInviteCtx(context.Background(), invite)
+}
+
+func (obj *Customer) SendInviteCtx(ctx context.Context, invite *CustomerInvite) error {
+	endpoint := fmt.Sprintf("/admin/customers/%d/send_invite.json", obj.Id)
+
+	if invite == nil {
+		invite = &CustomerInvite{}
+	}
+	body := map[string]*CustomerInvite{}
+	body["customer_invite"] = invite
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != 201 {
+		return newResponseError(status, res)
+	}
 
 	return nil
 }
+
+// AccountActivationURL returns a one-time URL for the customer to activate
+// their account, for sending through your own channels.
+func (obj *Customer) AccountActivationURL() (string, error) {
+	return obj.AccountActivationURLCtx(context.Background())
+}
+
+func (obj *Customer) AccountActivationURLCtx(ctx context.Context) (string, error) {
+	endpoint := fmt.Sprintf("/admin/customers/%d/account_activation_url.json", obj.Id)
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "POST", nil, &bytes.Buffer{})
+
+	if err != nil {
+		return "", err
+	}
+
+	if status != 200 {
+		return "", newResponseError(status, res)
+	}
+
+	r := map[string]string{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return "", err
+	}
+
+	return r["account_activation_url"], nil
+}
@@ -0,0 +1,206 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+)
+
+// DefaultAddress is the former name of CustomerAddress.
+type DefaultAddress = CustomerAddress
+
+type CustomerAddress struct {
+	Address1 string `json:"address1"`
+
+	Address2 string `json:"address2"`
+
+	City string `json:"city"`
+
+	Company string `json:"company"`
+
+	Country string `json:"country"`
+
+	CustomerId int64 `json:"customer_id,omitempty"`
+
+	FirstName string `json:"first_name"`
+
+	Id int64 `json:"id"`
+
+	LastName string `json:"last_name"`
+
+	Phone string `json:"phone"`
+
+	Province string `json:"province"`
+
+	Zip string `json:"zip"`
+
+	Name string `json:"name"`
+
+	ProvinceCode string `json:"province_code"`
+
+	CountryCode string `json:"country_code"`
+
+	CountryName string `json:"country_name"`
+
+	Default bool `json:"default"`
+
+	api *API
+}
+
+// ListAddresses fetches the customer's address book. The Addresses field
+// holds the addresses embedded in the customer when it was loaded.
+func (obj *Customer) ListAddresses() ([]CustomerAddress, error) {
+	return obj.ListAddressesCtx(context.Background())
+}
+
+func (obj *Customer) ListAddressesCtx(ctx context.Context) ([]CustomerAddress, error) {
+	endpoint := fmt.Sprintf("/admin/customers/%d/addresses.json", obj.Id)
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]CustomerAddress{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["addresses"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = obj.api
+	}
+
+	return result, nil
+}
+
+func (obj *Customer) Address(id int64) (*CustomerAddress, error) {
+	return obj.AddressCtx(context.Background(), id)
+}
+
+func (obj *Customer) AddressCtx(ctx context.Context, id int64) (*CustomerAddress, error) {
+	endpoint := fmt.Sprintf("/admin/customers/%d/addresses/%d.json", obj.Id, id)
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]CustomerAddress{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["customer_address"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = obj.api
+
+	return &result, nil
+}
+
+func (obj *Customer) NewAddress() *CustomerAddress {
+	return &CustomerAddress{CustomerId: obj.Id, api: obj.api}
+}
+
+func (obj *CustomerAddress) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *CustomerAddress) SaveCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/customers/%d/addresses/%d.json", obj.CustomerId, obj.Id)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.Id == 0 {
+		endpoint = fmt.Sprintf("/admin/customers/%d/addresses.json", obj.CustomerId)
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	body := map[string]*CustomerAddress{}
+	body["address"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	return obj.request(ctx, endpoint, method, expectedStatus, buf)
+}
+
+// SetDefault makes this the customer's default address.
+func (obj *CustomerAddress) SetDefault() error {
+	return obj.SetDefaultCtx(context.Background())
+}
+
+func (obj *CustomerAddress) SetDefaultCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/customers/%d/addresses/%d/default.json", obj.CustomerId, obj.Id)
+	return obj.request(ctx, endpoint, "PUT", 200, &bytes.Buffer{})
+}
+
+func (obj *CustomerAddress) request(ctx context.Context, endpoint, method string, expectedStatus int, body *bytes.Buffer) error {
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, method, nil, body)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]CustomerAddress{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	*obj = r["customer_address"]
+	obj.api = api
+
+	return nil
+}
+
+// Delete removes the address. Shopify refuses to delete the default address.
+func (obj *CustomerAddress) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *CustomerAddress) DeleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/customers/%d/addresses/%d.json", obj.CustomerId, obj.Id)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	return nil
+}
@@ -3,74 +3,85 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
-
-	"time"
 )
 
 type CustomerSavedSearch struct {
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
 	Id int64 `json:"id"`
 
-	Name time.Time `json:"name"`
+	Name string `json:"name"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
-	Query time.Time `json:"query"`
+	Query string `json:"query"`
 
 	api *API
 }
 
 func (api *API) CustomerSavedSearches() ([]CustomerSavedSearch, error) {
-	res, status, err := api.request("/admin/customer_saved_searches.json", "GET", nil, nil)
+	return api.CustomerSavedSearchesCtx(context.Background())
+}
+
+func (api *API) CustomerSavedSearchesCtx(ctx context.Context) ([]CustomerSavedSearch, error) {
+	res, status, err := api.requestContext(ctx, "/admin/customer_saved_searches.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]CustomerSavedSearch{}
 	err = json.NewDecoder(res).Decode(r)
 
-	fmt.Printf("things are: %v\n\n", *r)
-
 	result := (*r)["customer_saved_searches"]
 
 	if err != nil {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
+// CustomerSavedSearchesIter walks every page of customer_saved_searches.
+func (api *API) CustomerSavedSearchesIter(ctx context.Context, options *ListOptions) *Iterator[CustomerSavedSearch] {
+	return newIterator(ctx, api, "/admin/customer_saved_searches.json", "customer_saved_searches", options,
+		func(v *CustomerSavedSearch) int64 { return v.Id },
+		func(v *CustomerSavedSearch) { v.api = api })
+}
+
 func (api *API) CustomerSavedSearch(id int64) (*CustomerSavedSearch, error) {
+	return api.CustomerSavedSearchCtx(context.Background(), id)
+}
+
+func (api *API) CustomerSavedSearchCtx(ctx context.Context, id int64) (*CustomerSavedSearch, error) {
 	endpoint := fmt.Sprintf("/admin/customer_saved_searches/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]CustomerSavedSearch{}
 	err = json.NewDecoder(res).Decode(&r)
 
-	fmt.Printf("things are: %v\n\n", r)
-
 	result := r["customer_saved_search"]
 
 	if err != nil {
@@ -87,9 +98,13 @@ func (api *API) NewCustomerSavedSearch() *CustomerSavedSearch {
 }
 
 func (obj *CustomerSavedSearch) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *CustomerSavedSearch) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/customer_saved_searches/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/customer_saved_searches.json")
@@ -107,20 +122,15 @@ func (obj *CustomerSavedSearch) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]CustomerSavedSearch{}
@@ -130,11 +140,18 @@ func (obj *CustomerSavedSearch) Save() error {
 		return err
 	}
 
-	fmt.Printf("things are: %v\n\n", r)
-
 	*obj = r["customer_saved_search"]
-
-	fmt.Printf("things are: %v\n\n", res)
+	obj.api = api
 
 	return nil
 }
+
+// Customers runs the saved search.
+func (obj *CustomerSavedSearch) Customers() ([]Customer, error) {
+	return obj.CustomersCtx(context.Background())
+}
+
+func (obj *CustomerSavedSearch) CustomersCtx(ctx context.Context) ([]Customer, error) {
+	endpoint := fmt.Sprintf("/admin/customer_saved_searches/%d/customers.json", obj.Id)
+	return obj.api.customers(ctx, endpoint)
+}
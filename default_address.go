@@ -1,35 +0,0 @@
-package shopify
-
-type DefaultAddress struct {
-	Address1 string `json:"address1"`
-
-	Address2 string `json:"address2"`
-
-	City string `json:"city"`
-
-	Company string `json:"company"`
-
-	Country string `json:"country"`
-
-	FirstName string `json:"first_name"`
-
-	Id int64 `json:"id"`
-
-	LastName string `json:"last_name"`
-
-	Phone string `json:"phone"`
-
-	Province string `json:"province"`
-
-	Zip string `json:"zip"`
-
-	Name string `json:"name"`
-
-	ProvinceCode string `json:"province_code"`
-
-	CountryCode string `json:"country_code"`
-
-	CountryName string `json:"country_name"`
-
-	Default bool `json:"default"`
-}
@@ -0,0 +1,465 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"crypto/rand"
+
+	"encoding/json"
+
+	"fmt"
+
+	"io"
+
+	"math"
+
+	"math/big"
+
+	"strings"
+
+	"time"
+
+	"unicode"
+)
+
+// DISCOUNT_CODE_BATCH_LIMIT is the number of codes the batch endpoint accepts
+// per job.
+const DISCOUNT_CODE_BATCH_LIMIT = 100
+
+// DISCOUNT_CODE_POLL_INTERVAL is how often DiscountCodeCreation.Wait polls by
+// default.
+const DISCOUNT_CODE_POLL_INTERVAL = time.Second
+
+type DiscountCode struct {
+	Code string `json:"code"`
+
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	// Errors holds the reasons a code of a batch job wasn't created, keyed
+	// by field.
+	Errors map[string][]string `json:"errors,omitempty"`
+
+	Id int64 `json:"id,omitempty"`
+
+	PriceRuleId int64 `json:"price_rule_id,omitempty"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
+
+	UsageCount int64 `json:"usage_count,omitempty"`
+
+	api *API
+}
+
+// OrderDiscountCode is a discount code applied to an order or checkout.
+type OrderDiscountCode struct {
+	Amount Money `json:"amount,omitzero"`
+
+	Code string `json:"code"`
+
+	Type string `json:"type"`
+}
+
+func (obj *PriceRule) DiscountCodes() ([]DiscountCode, error) {
+	return obj.DiscountCodesCtx(context.Background())
+}
+
+func (obj *PriceRule) DiscountCodesCtx(ctx context.Context) ([]DiscountCode, error) {
+	endpoint := fmt.Sprintf("/admin/price_rules/%d/discount_codes.json", obj.Id)
+	return obj.api.discountCodes(ctx, endpoint)
+}
+
+// DiscountCodesIter walks every page of the price rule's discount codes.
+func (obj *PriceRule) DiscountCodesIter(ctx context.Context, options *ListOptions) *Iterator[DiscountCode] {
+	api := obj.api
+	endpoint := fmt.Sprintf("/admin/price_rules/%d/discount_codes.json", obj.Id)
+	return newIterator(ctx, api, endpoint, "discount_codes", options,
+		func(v *DiscountCode) int64 { return v.Id },
+		func(v *DiscountCode) { v.api = api })
+}
+
+func (api *API) discountCodes(ctx context.Context, endpoint string) ([]DiscountCode, error) {
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]DiscountCode{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["discount_codes"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = api
+	}
+
+	return result, nil
+}
+
+func (obj *PriceRule) DiscountCode(id int64) (*DiscountCode, error) {
+	return obj.DiscountCodeCtx(context.Background(), id)
+}
+
+func (obj *PriceRule) DiscountCodeCtx(ctx context.Context, id int64) (*DiscountCode, error) {
+	endpoint := fmt.Sprintf("/admin/price_rules/%d/discount_codes/%d.json", obj.Id, id)
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]DiscountCode{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["discount_code"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = obj.api
+
+	return &result, nil
+}
+
+func (obj *PriceRule) NewDiscountCode(code string) *DiscountCode {
+	return &DiscountCode{Code: code, PriceRuleId: obj.Id, api: obj.api}
+}
+
+func (obj *DiscountCode) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *DiscountCode) SaveCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/price_rules/%d/discount_codes/%d.json", obj.PriceRuleId, obj.Id)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.Id == 0 {
+		endpoint = fmt.Sprintf("/admin/price_rules/%d/discount_codes.json", obj.PriceRuleId)
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	body := map[string]*DiscountCode{}
+	body["discount_code"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]DiscountCode{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["discount_code"]
+	obj.api = api
+
+	return nil
+}
+
+func (obj *DiscountCode) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *DiscountCode) DeleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/price_rules/%d/discount_codes/%d.json", obj.PriceRuleId, obj.Id)
+	method := "DELETE"
+	expectedStatus := 204
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	return nil
+}
+
+// Statuses of a DiscountCodeCreation.
+const (
+	DiscountCodeCreationQueued    = "queued"
+	DiscountCodeCreationRunning   = "running"
+	DiscountCodeCreationCompleted = "completed"
+)
+
+// DiscountCodeCreation is a batch job creating discount codes, run by
+// Shopify in the background.
+type DiscountCodeCreation struct {
+	CodesCount int64 `json:"codes_count"`
+
+	CompletedAt Timestamp `json:"completed_at,omitzero"`
+
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	FailedCount int64 `json:"failed_count"`
+
+	Id int64 `json:"id"`
+
+	ImportedCount int64 `json:"imported_count"`
+
+	Logs []string `json:"logs"`
+
+	PriceRuleId int64 `json:"price_rule_id"`
+
+	StartedAt Timestamp `json:"started_at,omitzero"`
+
+	Status string `json:"status"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
+
+	api *API
+}
+
+// CreateDiscountCodes starts a batch job creating up to
+// DISCOUNT_CODE_BATCH_LIMIT codes. Use ImportDiscountCodes for more.
+func (obj *PriceRule) CreateDiscountCodes(codes []string) (*DiscountCodeCreation, error) {
+	return obj.CreateDiscountCodesCtx(context.Background(), codes)
+}
+
+func (obj *PriceRule) CreateDiscountCodesCtx(ctx context.Context, codes []string) (*DiscountCodeCreation, error) {
+	if len(codes) > DISCOUNT_CODE_BATCH_LIMIT {
+		return nil, fmt.Errorf("shopify: %d discount codes, a batch takes at most %d", len(codes), DISCOUNT_CODE_BATCH_LIMIT)
+	}
+
+	endpoint := fmt.Sprintf("/admin/price_rules/%d/batch.json", obj.Id)
+
+	list := make([]map[string]string, len(codes))
+	for i, code := range codes {
+		list[i] = map[string]string{"code": code}
+	}
+	body := map[string]interface{}{}
+	body["discount_codes"] = list
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DiscountCodeCreation{api: obj.api}
+	err = result.request(ctx, endpoint, "POST", 201, buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ImportDiscountCodes creates any number of codes, one batch job of
+// DISCOUNT_CODE_BATCH_LIMIT codes at a time, waiting for each job to finish
+// before starting the next. It returns the codes that could not be created,
+// with their Errors set.
+func (obj *PriceRule) ImportDiscountCodes(codes []string) ([]DiscountCode, error) {
+	return obj.ImportDiscountCodesCtx(context.Background(), codes)
+}
+
+func (obj *PriceRule) ImportDiscountCodesCtx(ctx context.Context, codes []string) ([]DiscountCode, error) {
+	failed := []DiscountCode{}
+
+	for start := 0; start < len(codes); start += DISCOUNT_CODE_BATCH_LIMIT {
+		end := min(start+DISCOUNT_CODE_BATCH_LIMIT, len(codes))
+
+		job, err := obj.CreateDiscountCodesCtx(ctx, codes[start:end])
+		if err != nil {
+			return failed, err
+		}
+		if err = job.Wait(ctx, DISCOUNT_CODE_POLL_INTERVAL); err != nil {
+			return failed, err
+		}
+		if job.FailedCount == 0 {
+			continue
+		}
+
+		results, err := job.CodesCtx(ctx)
+		if err != nil {
+			return failed, err
+		}
+		for _, code := range results {
+			if len(code.Errors) > 0 {
+				failed = append(failed, code)
+			}
+		}
+	}
+
+	return failed, nil
+}
+
+// Refresh reloads the job's status and counts.
+func (obj *DiscountCodeCreation) Refresh(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/price_rules/%d/batch/%d.json", obj.PriceRuleId, obj.Id)
+	return obj.request(ctx, endpoint, "GET", 200, nil)
+}
+
+// Done reports whether the job finished.
+func (obj *DiscountCodeCreation) Done() bool {
+	return obj.Status == DiscountCodeCreationCompleted
+}
+
+// Wait polls the job every interval until it finishes.
+func (obj *DiscountCodeCreation) Wait(ctx context.Context, interval time.Duration) error {
+	for !obj.Done() {
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+		if err := obj.Refresh(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Codes lists the codes of a finished job. Codes that failed to be created
+// have Errors set and no Id.
+func (obj *DiscountCodeCreation) Codes() ([]DiscountCode, error) {
+	return obj.CodesCtx(context.Background())
+}
+
+func (obj *DiscountCodeCreation) CodesCtx(ctx context.Context) ([]DiscountCode, error) {
+	endpoint := fmt.Sprintf("/admin/price_rules/%d/batch/%d/discount_codes.json", obj.PriceRuleId, obj.Id)
+	return obj.api.discountCodes(ctx, endpoint)
+}
+
+func (obj *DiscountCodeCreation) request(ctx context.Context, endpoint, method string, expectedStatus int, body io.Reader) error {
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, method, nil, body)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]DiscountCodeCreation{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	*obj = r["discount_code_creation"]
+	obj.api = api
+
+	return nil
+}
+
+// DISCOUNT_CODE_ALPHABET leaves out characters easily mistaken for one
+// another, such as 0 and O or 1 and I.
+const DISCOUNT_CODE_ALPHABET = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// DiscountCodeGenerator produces random discount codes such as "SUMMER-7KQ2MXH4",
+// never returning the same code twice. Codes are compared case-insensitively,
+// as Shopify does.
+type DiscountCodeGenerator struct {
+	Prefix   string // prepended to every code, e.g. "SUMMER-"
+	Length   int    // number of random characters, 8 if unset
+	Alphabet string // characters to draw from, DISCOUNT_CODE_ALPHABET if unset
+
+	seen map[string]bool
+}
+
+// Exclude marks codes, e.g. ones already in use, as taken.
+func (g *DiscountCodeGenerator) Exclude(codes ...string) {
+	if g.seen == nil {
+		g.seen = map[string]bool{}
+	}
+	for _, code := range codes {
+		g.seen[strings.ToUpper(code)] = true
+	}
+}
+
+// Generate returns n codes, unique among themselves and every code generated
+// or excluded before. It fails if Length and Alphabet leave too few possible
+// codes to pick n more from at random. Codes are only marked as taken once
+// all n have been generated.
+func (g *DiscountCodeGenerator) Generate(n int) ([]string, error) {
+	length := g.Length
+	if length <= 0 {
+		length = 8
+	}
+	alphabet := []rune(g.Alphabet)
+	if len(alphabet) == 0 {
+		alphabet = []rune(DISCOUNT_CODE_ALPHABET)
+	}
+	if g.seen == nil {
+		g.seen = map[string]bool{}
+	}
+
+	// Random picks slow down as the space fills up; refuse past half of it.
+	// Letters differing only in case make the same code.
+	distinct := map[rune]bool{}
+	for _, r := range alphabet {
+		distinct[unicode.ToUpper(r)] = true
+	}
+	space := math.Pow(float64(len(distinct)), float64(length))
+	if float64(len(g.seen)+n) > space/2 {
+		return nil, fmt.Errorf("shopify: %d characters from a %d character alphabet are too few for %d more codes", length, len(distinct), n)
+	}
+
+	max := big.NewInt(int64(len(alphabet)))
+	codes := make([]string, 0, n)
+	batch := map[string]bool{}
+	buf := make([]rune, length)
+	for len(codes) < n {
+		for i := range buf {
+			r, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, err
+			}
+			buf[i] = alphabet[r.Int64()]
+		}
+
+		code := g.Prefix + string(buf)
+		key := strings.ToUpper(code)
+		if g.seen[key] || batch[key] {
+			continue
+		}
+		batch[key] = true
+		codes = append(codes, code)
+	}
+
+	for key := range batch {
+		g.seen[key] = true
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,54 @@
+package shopify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiscountCodeGenerator(t *testing.T) {
+	g := &DiscountCodeGenerator{Prefix: "SUMMER-", Length: 3, Alphabet: "ABCDEFGH"}
+	g.Exclude("SUMMER-AAA")
+
+	codes, err := g.Generate(200)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	more, err := g.Generate(50)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	seen := map[string]bool{"SUMMER-AAA": true}
+	for _, code := range append(codes, more...) {
+		if seen[code] {
+			t.Fatalf("Code %s generated twice", code)
+		}
+		seen[code] = true
+
+		random := strings.TrimPrefix(code, "SUMMER-")
+		if len(random) != 3 || strings.Trim(random, "ABCDEFGH") != "" {
+			t.Errorf("Unexpected code %s", code)
+		}
+	}
+
+	if _, err = g.Generate(10); err == nil {
+		t.Errorf("Expected an error once half of the 512 codes are taken")
+	}
+}
+
+func TestDiscountCodeGeneratorIgnoresCase(t *testing.T) {
+	g := &DiscountCodeGenerator{Length: 2, Alphabet: "aAbB"}
+	g.Exclude("aa")
+
+	codes, err := g.Generate(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.ToUpper(codes[0]) == "AA" {
+		t.Errorf("Expected excluded aa to rule out %s", codes[0])
+	}
+
+	if _, err = g.Generate(1); err == nil {
+		t.Errorf("Expected an error once half of the 4 codes are taken")
+	}
+}
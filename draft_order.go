@@ -0,0 +1,325 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+
+	"time"
+)
+
+// Statuses of a DraftOrder.
+const (
+	DraftOrderOpen        = "open"
+	DraftOrderInvoiceSent = "invoice_sent"
+	DraftOrderCompleted   = "completed"
+)
+
+// DraftOrder is an order built on behalf of a customer, such as a quote,
+// which becomes a real Order once completed.
+type DraftOrder struct {
+	AppliedDiscount *AppliedDiscount `json:"applied_discount,omitempty"`
+
+	BillingAddress *BillingAddress `json:"billing_address,omitempty"`
+
+	CompletedAt Timestamp `json:"completed_at,omitzero"`
+
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	Currency string `json:"currency,omitempty"`
+
+	// Customer links the draft order to an existing customer; only its Id
+	// is sent.
+	Customer *Customer `json:"customer,omitempty"`
+
+	Email string `json:"email,omitempty"`
+
+	Id int64 `json:"id,omitempty"`
+
+	InvoiceSentAt Timestamp `json:"invoice_sent_at,omitzero"`
+
+	InvoiceUrl string `json:"invoice_url,omitempty"`
+
+	LineItems []LineItem `json:"line_items,omitempty"`
+
+	Name string `json:"name,omitempty"`
+
+	Note string `json:"note,omitempty"`
+
+	NoteAttributes []NoteAttribute `json:"note_attributes,omitempty"`
+
+	OrderId int64 `json:"order_id,omitempty"`
+
+	ShippingAddress *BillingAddress `json:"shipping_address,omitempty"`
+
+	ShippingLine *ShippingLine `json:"shipping_line,omitempty"`
+
+	Status string `json:"status,omitempty"`
+
+	SubtotalPrice Money `json:"subtotal_price,omitzero"`
+
+	Tags string `json:"tags,omitempty"`
+
+	TaxExempt bool `json:"tax_exempt,omitempty"`
+
+	TaxesIncluded bool `json:"taxes_included,omitempty"`
+
+	TotalPrice Money `json:"total_price,omitzero"`
+
+	TotalTax Money `json:"total_tax,omitzero"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
+
+	UseCustomerDefaultAddress bool `json:"use_customer_default_address,omitempty"`
+
+	api *API
+}
+
+// MarshalJSON sends the linked customer as {"id": N}, so saving a draft
+// order doesn't overwrite the customer's details with blanks.
+func (obj DraftOrder) MarshalJSON() ([]byte, error) {
+	type draftOrder DraftOrder
+	type customer struct {
+		Id int64 `json:"id"`
+	}
+
+	v := struct {
+		draftOrder
+		Customer *customer `json:"customer,omitempty"`
+	}{draftOrder: draftOrder(obj)}
+	if obj.Customer != nil {
+		v.Customer = &customer{Id: obj.Customer.Id}
+	}
+
+	return json.Marshal(v)
+}
+
+type DraftOrdersOptions struct {
+	IDs          string    `url:"ids,omitempty"`
+	Limit        int       `url:"limit,omitempty"`
+	SinceID      int64     `url:"since_id,omitempty"`
+	Status       string    `url:"status,omitempty"`
+	UpdatedAtMin time.Time `url:"updated_at_min,omitempty"`
+	UpdatedAtMax time.Time `url:"updated_at_max,omitempty"`
+	Fields       string    `url:"fields,omitempty"`
+}
+
+func (api *API) DraftOrders(options *DraftOrdersOptions) ([]DraftOrder, error) {
+	return api.DraftOrdersCtx(context.Background(), options)
+}
+
+func (api *API) DraftOrdersCtx(ctx context.Context, options *DraftOrdersOptions) ([]DraftOrder, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/draft_orders.json?%v", qs)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]DraftOrder{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["draft_orders"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = api
+	}
+
+	return result, nil
+}
+
+// DraftOrdersIter walks every page of draft orders.
+func (api *API) DraftOrdersIter(ctx context.Context, options *DraftOrdersOptions) *Iterator[DraftOrder] {
+	return newIterator(ctx, api, "/admin/draft_orders.json", "draft_orders", options,
+		func(v *DraftOrder) int64 { return v.Id },
+		func(v *DraftOrder) { v.api = api })
+}
+
+func (api *API) DraftOrder(id int64) (*DraftOrder, error) {
+	return api.DraftOrderCtx(context.Background(), id)
+}
+
+func (api *API) DraftOrderCtx(ctx context.Context, id int64) (*DraftOrder, error) {
+	endpoint := fmt.Sprintf("/admin/draft_orders/%d.json", id)
+
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]DraftOrder{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["draft_order"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewDraftOrder() *DraftOrder {
+	return &DraftOrder{api: api}
+}
+
+func (obj *DraftOrder) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *DraftOrder) SaveCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/draft_orders/%d.json", obj.Id)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.Id == 0 {
+		endpoint = fmt.Sprintf("/admin/draft_orders.json")
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	body := map[string]*DraftOrder{}
+	body["draft_order"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	return obj.request(ctx, endpoint, method, expectedStatus, buf)
+}
+
+func (obj *DraftOrder) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *DraftOrder) DeleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/draft_orders/%d.json", obj.Id)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	return nil
+}
+
+// SendInvoice emails the customer an invoice with a link to pay for the
+// draft order. Empty arguments use the shop's defaults, e.g. the draft
+// order's Email for to.
+func (obj *DraftOrder) SendInvoice(to, subject, message string) error {
+	return obj.SendInvoiceCtx(context.Background(), to, subject, message)
+}
+
+func (obj *DraftOrder) SendInvoiceCtx(ctx context.Context, to, subject, message string) error {
+	endpoint := fmt.Sprintf("/admin/draft_orders/%d/send_invoice.json", obj.Id)
+
+	invoice := map[string]string{}
+	if to != "" {
+		invoice["to"] = to
+	}
+	if subject != "" {
+		invoice["subject"] = subject
+	}
+	if message != "" {
+		invoice["custom_message"] = message
+	}
+	body := map[string]interface{}{}
+	body["draft_order_invoice"] = invoice
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != 201 {
+		return newResponseError(status, res)
+	}
+
+	return nil
+}
+
+// Complete turns the draft order into an order, marked as paid unless
+// paymentPending is set, and returns that order.
+func (obj *DraftOrder) Complete(paymentPending bool) (*Order, error) {
+	return obj.CompleteCtx(context.Background(), paymentPending)
+}
+
+func (obj *DraftOrder) CompleteCtx(ctx context.Context, paymentPending bool) (*Order, error) {
+	endpoint := fmt.Sprintf("/admin/draft_orders/%d/complete.json?payment_pending=%t", obj.Id, paymentPending)
+
+	err := obj.request(ctx, endpoint, "PUT", 200, &bytes.Buffer{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.OrderId == 0 {
+		return nil, fmt.Errorf("shopify: draft order %d completed without an order", obj.Id)
+	}
+
+	return obj.api.OrderCtx(ctx, obj.OrderId)
+}
+
+// request sends body to endpoint and reloads the draft order from the
+// response.
+func (obj *DraftOrder) request(ctx context.Context, endpoint, method string, expectedStatus int, body *bytes.Buffer) error {
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, method, nil, body)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]DraftOrder{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	*obj = r["draft_order"]
+	obj.api = api
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors matched by ResponseError through errors.Is, e.g.
+//
+//	if errors.Is(err, shopify.ErrNotFound) { ... }
+var (
+	ErrBadRequest      = errors.New("shopify: bad request")
+	ErrUnauthorized    = errors.New("shopify: unauthorized")
+	ErrPaymentRequired = errors.New("shopify: payment required")
+	ErrForbidden       = errors.New("shopify: forbidden")
+	ErrNotFound        = errors.New("shopify: not found")
+	ErrValidation      = errors.New("shopify: validation failed")
+	ErrRateLimited     = errors.New("shopify: rate limited")
+	ErrServer          = errors.New("shopify: server error")
+
+	// ErrUnexpectedStatus matches UnexpectedStatusError.
+	ErrUnexpectedStatus = errors.New("shopify: unexpected status")
+)
+
+// ResponseError is returned when Shopify answers with an unexpected status.
+type ResponseError struct {
+	Status    int
+	RequestID string // X-Request-Id, useful when contacting Shopify support
+	Method    string
+	Endpoint  string
+
+	// Message holds errors Shopify reports without a field, e.g. "Not Found".
+	Message string
+	// Errors holds field level validation errors, e.g. "title": ["can't be blank"].
+	Errors map[string][]string
+}
+
+type errorResponse struct {
+	Errors interface{} `json:"errors"`
+	Error  string      `json:"error"`
+}
+
+// UnexpectedStatusError is returned when Shopify answers a call successfully
+// but not with the status the call expects, e.g. 200 instead of 201 for a
+// create.
+type UnexpectedStatusError struct {
+	Status int
+}
+
+func (e *UnexpectedStatusError) Error() string {
+	return fmt.Sprintf("shopify: unexpected status %d %s", e.Status, http.StatusText(e.Status))
+}
+
+func (e *UnexpectedStatusError) Is(target error) bool {
+	return target == ErrUnexpectedStatus
+}
+
+// newResponseError is returned by calls that got a status other than the one
+// they expect. Failed calls already got a ResponseError from requestHeader, so
+// this is normally an UnexpectedStatusError.
+func newResponseError(status int, body *bytes.Buffer) error {
+	if status >= 400 {
+		return parseResponseError(status, body)
+	}
+	return &UnexpectedStatusError{Status: status}
+}
+
+// parseResponseError builds a ResponseError for status from the response body,
+// which Shopify sends in several shapes: a string, a list of strings, or a
+// map of field name to messages.
+func parseResponseError(status int, body *bytes.Buffer) *ResponseError {
+	e := &ResponseError{Status: status}
+
+	r := errorResponse{}
+	if body == nil || json.Unmarshal(body.Bytes(), &r) != nil {
+		return e
+	}
+
+	switch v := r.Errors.(type) {
+	case string:
+		e.Message = v
+	case []interface{}:
+		e.Message = strings.Join(stringsOf(v), "; ")
+	case map[string]interface{}:
+		e.Errors = map[string][]string{}
+		for field, msgs := range v {
+			switch m := msgs.(type) {
+			case []interface{}:
+				e.Errors[field] = stringsOf(m)
+			default:
+				e.Errors[field] = []string{fmt.Sprint(m)}
+			}
+		}
+	}
+	if e.Message == "" {
+		e.Message = r.Error
+	}
+	return e
+}
+
+func stringsOf(values []interface{}) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = fmt.Sprint(v)
+	}
+	return result
+}
+
+func (e *ResponseError) Error() string {
+	msg := fmt.Sprintf("shopify: %d %s", e.Status, http.StatusText(e.Status))
+	if e.Endpoint != "" {
+		msg = fmt.Sprintf("shopify: %s %s: %d %s", e.Method, e.Endpoint, e.Status, http.StatusText(e.Status))
+	}
+
+	details := []string{}
+	if e.Message != "" {
+		details = append(details, e.Message)
+	}
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		details = append(details, fmt.Sprintf("%s %s", field, strings.Join(e.Errors[field], ", ")))
+	}
+	if len(details) > 0 {
+		msg = fmt.Sprintf("%s: %s", msg, strings.Join(details, "; "))
+	}
+	return msg
+}
+
+// Is matches the sentinel error for e's status code.
+func (e *ResponseError) Is(target error) bool {
+	switch {
+	case e.Status == 400:
+		return target == ErrBadRequest
+	case e.Status == 401:
+		return target == ErrUnauthorized
+	case e.Status == 402:
+		return target == ErrPaymentRequired
+	case e.Status == 403:
+		return target == ErrForbidden
+	case e.Status == 404:
+		return target == ErrNotFound
+	case e.Status == 422:
+		return target == ErrValidation
+	case e.Status == 429:
+		return target == ErrRateLimited
+	case e.Status >= 500:
+		return target == ErrServer
+	}
+	return false
+}
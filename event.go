@@ -1,11 +1,11 @@
 package shopify
 
 import (
+	"context"
+
 	"encoding/json"
 
 	"fmt"
-
-	"time"
 )
 
 type Event struct {
@@ -13,7 +13,7 @@ type Event struct {
 
 	Body string `json:"body"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
 	Id int64 `json:"id"`
 
@@ -35,14 +35,18 @@ type Event struct {
 }
 
 func (api *API) Events() ([]Event, error) {
-	res, status, err := api.request("/admin/events.json", "GET", nil, nil)
+	return api.EventsCtx(context.Background())
+}
+
+func (api *API) EventsCtx(ctx context.Context) ([]Event, error) {
+	res, status, err := api.requestContext(ctx, "/admin/events.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Event{}
@@ -54,24 +58,35 @@ func (api *API) Events() ([]Event, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
+// EventsIter walks every page of events.
+func (api *API) EventsIter(ctx context.Context, options *ListOptions) *Iterator[Event] {
+	return newIterator(ctx, api, "/admin/events.json", "events", options,
+		func(v *Event) int64 { return v.Id },
+		func(v *Event) { v.api = api })
+}
+
 func (api *API) Event(id int64) (*Event, error) {
+	return api.EventCtx(context.Background(), id)
+}
+
+func (api *API) EventCtx(ctx context.Context, id int64) (*Event, error) {
 	endpoint := fmt.Sprintf("/admin/events/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Event{}
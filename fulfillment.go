@@ -0,0 +1,229 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+)
+
+type Fulfillment struct {
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	Id int64 `json:"id,omitempty"`
+
+	LineItems []LineItem `json:"line_items,omitempty"`
+
+	LocationId int64 `json:"location_id,omitempty"`
+
+	Name string `json:"name,omitempty"`
+
+	NotifyCustomer bool `json:"notify_customer,omitempty"`
+
+	OrderId int64 `json:"order_id,omitempty"`
+
+	Service string `json:"service,omitempty"`
+
+	ShipmentStatus string `json:"shipment_status,omitempty"`
+
+	Status string `json:"status,omitempty"`
+
+	TrackingCompany string `json:"tracking_company,omitempty"`
+
+	TrackingNumber string `json:"tracking_number,omitempty"`
+
+	TrackingNumbers []string `json:"tracking_numbers,omitempty"`
+
+	TrackingUrl string `json:"tracking_url,omitempty"`
+
+	TrackingUrls []string `json:"tracking_urls,omitempty"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
+
+	api *API
+}
+
+// ListFulfillments fetches the order's fulfillments. The Fulfillments field
+// holds the ones embedded in the order when it was loaded.
+func (obj *Order) ListFulfillments() ([]Fulfillment, error) {
+	return obj.ListFulfillmentsCtx(context.Background())
+}
+
+func (obj *Order) ListFulfillmentsCtx(ctx context.Context) ([]Fulfillment, error) {
+	endpoint := fmt.Sprintf("/admin/orders/%d/fulfillments.json", obj.Id)
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]Fulfillment{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["fulfillments"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = obj.api
+	}
+
+	return result, nil
+}
+
+func (obj *Order) Fulfillment(id int64) (*Fulfillment, error) {
+	return obj.FulfillmentCtx(context.Background(), id)
+}
+
+func (obj *Order) FulfillmentCtx(ctx context.Context, id int64) (*Fulfillment, error) {
+	endpoint := fmt.Sprintf("/admin/orders/%d/fulfillments/%d.json", obj.Id, id)
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]Fulfillment{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["fulfillment"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = obj.api
+
+	return &result, nil
+}
+
+// NewFulfillment returns an unsaved fulfillment for the order. Leave
+// LineItems empty to fulfill every remaining line item, or list the items
+// with their Id and Quantity set.
+func (obj *Order) NewFulfillment() *Fulfillment {
+	return &Fulfillment{OrderId: obj.Id, api: obj.api}
+}
+
+// Save creates the fulfillment or updates it, e.g. with new tracking numbers.
+func (obj *Fulfillment) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Fulfillment) SaveCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/orders/%d/fulfillments/%d.json", obj.OrderId, obj.Id)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.Id == 0 {
+		endpoint = fmt.Sprintf("/admin/orders/%d/fulfillments.json", obj.OrderId)
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	body := map[string]*Fulfillment{}
+	body["fulfillment"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	return obj.request(ctx, endpoint, method, expectedStatus, buf)
+}
+
+// UpdateTracking replaces the fulfillment's tracking information, optionally
+// emailing the customer about it. url and company may be empty.
+func (obj *Fulfillment) UpdateTracking(number, url, company string, notifyCustomer bool) error {
+	return obj.UpdateTrackingCtx(context.Background(), number, url, company, notifyCustomer)
+}
+
+func (obj *Fulfillment) UpdateTrackingCtx(ctx context.Context, number, url, company string, notifyCustomer bool) error {
+	endpoint := fmt.Sprintf("/admin/orders/%d/fulfillments/%d.json", obj.OrderId, obj.Id)
+
+	fulfillment := map[string]interface{}{
+		"id":               obj.Id,
+		"notify_customer":  notifyCustomer,
+		"tracking_numbers": []string{number},
+		"tracking_urls":    []string{},
+	}
+	if url != "" {
+		fulfillment["tracking_urls"] = []string{url}
+	}
+	if company != "" {
+		fulfillment["tracking_company"] = company
+	}
+
+	body := map[string]interface{}{
+		"fulfillment": fulfillment,
+	}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	return obj.request(ctx, endpoint, "PUT", 200, buf)
+}
+
+// Complete marks a pending fulfillment as successful.
+func (obj *Fulfillment) Complete() error {
+	return obj.CompleteCtx(context.Background())
+}
+
+func (obj *Fulfillment) CompleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/orders/%d/fulfillments/%d/complete.json", obj.OrderId, obj.Id)
+	return obj.request(ctx, endpoint, "POST", 200, &bytes.Buffer{})
+}
+
+func (obj *Fulfillment) Cancel() error {
+	return obj.CancelCtx(context.Background())
+}
+
+func (obj *Fulfillment) CancelCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/orders/%d/fulfillments/%d/cancel.json", obj.OrderId, obj.Id)
+	return obj.request(ctx, endpoint, "POST", 200, &bytes.Buffer{})
+}
+
+// request sends body to endpoint and reloads the fulfillment from the
+// response.
+func (obj *Fulfillment) request(ctx context.Context, endpoint, method string, expectedStatus int, body *bytes.Buffer) error {
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, method, nil, body)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]Fulfillment{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	*obj = r["fulfillment"]
+	obj.api = api
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+)
+
+// FulfillmentOrder is the group of an order's line items assigned to one
+// location for fulfillment. Shopify creates them with the order.
+type FulfillmentOrder struct {
+	AssignedLocation FulfillmentOrderLocation `json:"assigned_location"`
+
+	AssignedLocationId int64 `json:"assigned_location_id"`
+
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	FulfillAt Timestamp `json:"fulfill_at,omitzero"`
+
+	Id int64 `json:"id"`
+
+	LineItems []FulfillmentOrderLineItem `json:"line_items"`
+
+	OrderId int64 `json:"order_id"`
+
+	RequestStatus string `json:"request_status"`
+
+	ShopId int64 `json:"shop_id"`
+
+	Status string `json:"status"`
+
+	SupportedActions []string `json:"supported_actions"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
+
+	api *API
+}
+
+type FulfillmentOrderLocation struct {
+	Address1 string `json:"address1"`
+
+	Address2 string `json:"address2"`
+
+	City string `json:"city"`
+
+	CountryCode string `json:"country_code"`
+
+	LocationId int64 `json:"location_id"`
+
+	Name string `json:"name"`
+
+	Phone string `json:"phone"`
+
+	Province string `json:"province"`
+
+	Zip string `json:"zip"`
+}
+
+type FulfillmentOrderLineItem struct {
+	FulfillableQuantity int64 `json:"fulfillable_quantity"`
+
+	FulfillmentOrderId int64 `json:"fulfillment_order_id"`
+
+	Id int64 `json:"id"`
+
+	InventoryItemId int64 `json:"inventory_item_id"`
+
+	LineItemId int64 `json:"line_item_id"`
+
+	Quantity int64 `json:"quantity"`
+
+	ShopId int64 `json:"shop_id"`
+
+	VariantId int64 `json:"variant_id"`
+}
+
+func (obj *Order) FulfillmentOrders() ([]FulfillmentOrder, error) {
+	return obj.FulfillmentOrdersCtx(context.Background())
+}
+
+func (obj *Order) FulfillmentOrdersCtx(ctx context.Context) ([]FulfillmentOrder, error) {
+	endpoint := fmt.Sprintf("/admin/orders/%d/fulfillment_orders.json", obj.Id)
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]FulfillmentOrder{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["fulfillment_orders"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = obj.api
+	}
+
+	return result, nil
+}
+
+func (api *API) FulfillmentOrder(id int64) (*FulfillmentOrder, error) {
+	return api.FulfillmentOrderCtx(context.Background(), id)
+}
+
+func (api *API) FulfillmentOrderCtx(ctx context.Context, id int64) (*FulfillmentOrder, error) {
+	endpoint := fmt.Sprintf("/admin/fulfillment_orders/%d.json", id)
+
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]FulfillmentOrder{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["fulfillment_order"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+// Move reassigns the fulfillment order's remaining line items to the
+// location. The receiver is updated to the original fulfillment order, now
+// closed if everything moved, and the fulfillment order at the new location
+// is returned.
+func (obj *FulfillmentOrder) Move(locationId int64) (*FulfillmentOrder, error) {
+	return obj.MoveCtx(context.Background(), locationId)
+}
+
+func (obj *FulfillmentOrder) MoveCtx(ctx context.Context, locationId int64) (*FulfillmentOrder, error) {
+	endpoint := fmt.Sprintf("/admin/fulfillment_orders/%d/move.json", obj.Id)
+
+	body := map[string]interface{}{
+		"fulfillment_order": map[string]int64{
+			"new_location_id": locationId,
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]*FulfillmentOrder{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if original := r["original_fulfillment_order"]; original != nil {
+		*obj = *original
+		obj.api = api
+	}
+
+	moved := r["moved_fulfillment_order"]
+	if moved == nil {
+		return nil, fmt.Errorf("shopify: fulfillment order %d was not moved", obj.Id)
+	}
+	moved.api = api
+
+	return moved, nil
+}
+
+// Cancel cancels the fulfillment order. Shopify replaces it with a new open
+// fulfillment order for the same line items, which is returned.
+func (obj *FulfillmentOrder) Cancel() (*FulfillmentOrder, error) {
+	return obj.CancelCtx(context.Background())
+}
+
+func (obj *FulfillmentOrder) CancelCtx(ctx context.Context) (*FulfillmentOrder, error) {
+	endpoint := fmt.Sprintf("/admin/fulfillment_orders/%d/cancel.json", obj.Id)
+
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, "POST", nil, &bytes.Buffer{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]*FulfillmentOrder{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cancelled := r["fulfillment_order"]; cancelled != nil {
+		*obj = *cancelled
+		obj.api = api
+	}
+
+	replacement := r["replacement_fulfillment_order"]
+	if replacement != nil {
+		replacement.api = api
+	}
+
+	return replacement, nil
+}
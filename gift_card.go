@@ -0,0 +1,451 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+
+	"io"
+
+	"strings"
+)
+
+// Values of GiftCardsOptions.Status.
+const (
+	GiftCardEnabled  = "enabled"
+	GiftCardDisabled = "disabled"
+)
+
+// GiftCardCode is a gift card's redeemable code. Shopify only returns it when
+// the card is created. It prints and marshals to JSON masked down to its
+// last four characters, e.g. "•••• •••• •••• 0d0d", so gift cards can be
+// logged safely; use Reveal to get the code itself. GiftCard.Save still
+// sends the full code.
+type GiftCardCode string
+
+// Reveal returns the unmasked code.
+func (c GiftCardCode) Reveal() string {
+	return string(c)
+}
+
+func (c GiftCardCode) String() string {
+	return maskGiftCardCode(string(c))
+}
+
+func (c GiftCardCode) GoString() string {
+	return fmt.Sprintf("%q", c.String())
+}
+
+func (c GiftCardCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// Format masks the code for every verb, including %v, %s, %q and %x.
+func (c GiftCardCode) Format(f fmt.State, verb rune) {
+	if verb == 'q' || (verb == 'v' && f.Flag('#')) {
+		fmt.Fprintf(f, "%q", c.String())
+		return
+	}
+	fmt.Fprint(f, c.String())
+}
+
+func maskGiftCardCode(code string) string {
+	if code == "" {
+		return ""
+	}
+	last := code
+	if len(last) > 4 {
+		last = last[len(last)-4:]
+	}
+	return "•••• •••• •••• " + last
+}
+
+type GiftCard struct {
+	ApiClientId int64 `json:"api_client_id,omitempty"`
+
+	Balance Money `json:"balance,omitzero"`
+
+	// Code is set on creation, to choose the code or read the generated one.
+	Code GiftCardCode `json:"code,omitempty"`
+
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	Currency string `json:"currency,omitempty"`
+
+	CustomerId int64 `json:"customer_id,omitempty"`
+
+	DisabledAt Timestamp `json:"disabled_at,omitzero"`
+
+	ExpiresOn Timestamp `json:"expires_on,omitzero"`
+
+	Id int64 `json:"id,omitempty"`
+
+	InitialValue Money `json:"initial_value,omitzero"`
+
+	LastCharacters string `json:"last_characters,omitempty"`
+
+	LineItemId int64 `json:"line_item_id,omitempty"`
+
+	Note string `json:"note,omitempty"`
+
+	OrderId int64 `json:"order_id,omitempty"`
+
+	TemplateSuffix string `json:"template_suffix,omitempty"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
+
+	UserId int64 `json:"user_id,omitempty"`
+
+	api *API
+}
+
+// MaskedCode returns the code as Shopify's admin shows it, from the last
+// characters Shopify returns for existing cards.
+func (obj *GiftCard) MaskedCode() string {
+	if obj.LastCharacters == "" {
+		return obj.Code.String()
+	}
+	return maskGiftCardCode(obj.LastCharacters)
+}
+
+// GiftCardAdjustment is a change to a gift card's balance.
+type GiftCardAdjustment struct {
+	Amount Money `json:"amount"`
+
+	ApiClientId int64 `json:"api_client_id,omitempty"`
+
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	GiftCardId int64 `json:"gift_card_id,omitempty"`
+
+	Id int64 `json:"id,omitempty"`
+
+	Note string `json:"note,omitempty"`
+
+	Number int64 `json:"number,omitempty"`
+
+	OrderTransactionId int64 `json:"order_transaction_id,omitempty"`
+
+	ProcessedAt Timestamp `json:"processed_at,omitzero"`
+
+	RemoteTransactionRef string `json:"remote_transaction_ref,omitempty"`
+
+	RemoteTransactionUrl string `json:"remote_transaction_url,omitempty"`
+
+	UserId int64 `json:"user_id,omitempty"`
+}
+
+type GiftCardsOptions struct {
+	Status  string `url:"status,omitempty"`
+	Limit   int    `url:"limit,omitempty"`
+	Page    int    `url:"page,omitempty"`
+	SinceID int64  `url:"since_id,omitempty"`
+	Fields  string `url:"fields,omitempty"`
+}
+
+func (api *API) GiftCards(options *GiftCardsOptions) ([]GiftCard, error) {
+	return api.GiftCardsCtx(context.Background(), options)
+}
+
+func (api *API) GiftCardsCtx(ctx context.Context, options *GiftCardsOptions) ([]GiftCard, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/gift_cards.json?%v", qs)
+	return api.giftCards(ctx, endpoint)
+}
+
+// GiftCardsIter walks every page of gift cards.
+func (api *API) GiftCardsIter(ctx context.Context, options *GiftCardsOptions) *Iterator[GiftCard] {
+	return newIterator(ctx, api, "/admin/gift_cards.json", "gift_cards", options,
+		func(v *GiftCard) int64 { return v.Id },
+		func(v *GiftCard) { v.api = api })
+}
+
+type giftCardSearchOptions struct {
+	Query string `url:"query"`
+	Order string `url:"order,omitempty"`
+	Limit int    `url:"limit,omitempty"`
+}
+
+// SearchGiftCards returns the gift cards matching query, e.g.
+// `last_characters:0d0d` or `balance:>0`.
+func (api *API) SearchGiftCards(query string) ([]GiftCard, error) {
+	return api.SearchGiftCardsCtx(context.Background(), query)
+}
+
+func (api *API) SearchGiftCardsCtx(ctx context.Context, query string) ([]GiftCard, error) {
+	qs := encodeOptions(&giftCardSearchOptions{Query: query})
+	endpoint := fmt.Sprintf("/admin/gift_cards/search.json?%v", qs)
+	return api.giftCards(ctx, endpoint)
+}
+
+func (api *API) giftCards(ctx context.Context, endpoint string) ([]GiftCard, error) {
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]GiftCard{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["gift_cards"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = api
+	}
+
+	return result, nil
+}
+
+// GiftCardsCount counts gift cards, optionally only those with status
+// GiftCardEnabled or GiftCardDisabled.
+func (api *API) GiftCardsCount(status string) (int, error) {
+	return api.GiftCardsCountCtx(context.Background(), status)
+}
+
+func (api *API) GiftCardsCountCtx(ctx context.Context, status string) (int, error) {
+	qs := encodeOptions(&GiftCardsOptions{Status: status})
+	endpoint := fmt.Sprintf("/admin/gift_cards/count.json?%v", qs)
+
+	res, code, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if code != 200 {
+		return 0, newResponseError(code, res)
+	}
+
+	r := map[string]int{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return r["count"], nil
+}
+
+func (api *API) GiftCard(id int64) (*GiftCard, error) {
+	return api.GiftCardCtx(context.Background(), id)
+}
+
+func (api *API) GiftCardCtx(ctx context.Context, id int64) (*GiftCard, error) {
+	endpoint := fmt.Sprintf("/admin/gift_cards/%d.json", id)
+
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]GiftCard{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["gift_card"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewGiftCard() *GiftCard {
+	return &GiftCard{api: api}
+}
+
+// Save creates the gift card, or updates what Shopify lets change afterwards:
+// its expiry date, note, template suffix and customer. A zero ExpiresOn
+// removes the expiry date. Use Adjust to change its balance.
+func (obj *GiftCard) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *GiftCard) SaveCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/gift_cards/%d.json", obj.Id)
+	method := "PUT"
+	expectedStatus := 200
+
+	type update struct {
+		ExpiresOn      Timestamp `json:"expires_on"` // null removes the expiry
+		Note           string    `json:"note,omitempty"`
+		TemplateSuffix string    `json:"template_suffix,omitempty"`
+		CustomerId     int64     `json:"customer_id,omitempty"`
+	}
+
+	body := map[string]interface{}{}
+	body["gift_card"] = &update{
+		ExpiresOn:      obj.ExpiresOn,
+		Note:           obj.Note,
+		TemplateSuffix: obj.TemplateSuffix,
+		CustomerId:     obj.CustomerId,
+	}
+
+	if obj.Id == 0 {
+		endpoint = fmt.Sprintf("/admin/gift_cards.json")
+		method = "POST"
+		expectedStatus = 201
+
+		// Send the code itself rather than its masked form.
+		type giftCard GiftCard
+		body["gift_card"] = &struct {
+			*giftCard
+			Code string `json:"code,omitempty"`
+		}{(*giftCard)(obj), obj.Code.Reveal()}
+	}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	return obj.request(ctx, endpoint, method, expectedStatus, buf)
+}
+
+// Disable permanently disables the gift card.
+func (obj *GiftCard) Disable() error {
+	return obj.DisableCtx(context.Background())
+}
+
+func (obj *GiftCard) DisableCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/gift_cards/%d/disable.json", obj.Id)
+
+	body := map[string]interface{}{
+		"gift_card": map[string]int64{"id": obj.Id},
+	}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	return obj.request(ctx, endpoint, "POST", 200, buf)
+}
+
+// request sends body to endpoint and reloads the gift card from the
+// response, keeping the code when Shopify leaves it out.
+func (obj *GiftCard) request(ctx context.Context, endpoint, method string, expectedStatus int, body io.Reader) error {
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, method, nil, body)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]GiftCard{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	code := obj.Code
+	*obj = r["gift_card"]
+	obj.api = api
+	if obj.Code == "" && strings.HasSuffix(string(code), obj.LastCharacters) {
+		obj.Code = code
+	}
+
+	return nil
+}
+
+func (obj *GiftCard) Adjustments() ([]GiftCardAdjustment, error) {
+	return obj.AdjustmentsCtx(context.Background())
+}
+
+func (obj *GiftCard) AdjustmentsCtx(ctx context.Context) ([]GiftCardAdjustment, error) {
+	endpoint := fmt.Sprintf("/admin/gift_cards/%d/adjustments.json", obj.Id)
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]GiftCardAdjustment{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["adjustments"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Adjust changes the gift card's balance by amount, which is negative to
+// debit the card, and reloads the card to pick up its new balance.
+func (obj *GiftCard) Adjust(amount Money, note string) (*GiftCardAdjustment, error) {
+	return obj.AdjustCtx(context.Background(), amount, note)
+}
+
+func (obj *GiftCard) AdjustCtx(ctx context.Context, amount Money, note string) (*GiftCardAdjustment, error) {
+	endpoint := fmt.Sprintf("/admin/gift_cards/%d/adjustments.json", obj.Id)
+
+	body := map[string]*GiftCardAdjustment{}
+	body["adjustment"] = &GiftCardAdjustment{Amount: amount, Note: note}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 201 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]GiftCardAdjustment{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := r["adjustment"]
+
+	endpoint = fmt.Sprintf("/admin/gift_cards/%d.json", obj.Id)
+	if err = obj.request(ctx, endpoint, "GET", 200, nil); err != nil {
+		return &result, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,16 @@
+module github.com/boourns/go_shopify
+
+go 1.24
+
+require (
+	github.com/fsnotify/fsnotify v1.10.1
+	github.com/google/go-querystring v1.2.0
+	github.com/gorilla/context v1.1.2
+	github.com/gorilla/sessions v1.4.0
+	github.com/jpillora/backoff v1.0.0
+)
+
+require (
+	github.com/gorilla/securecookie v1.1.2 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+)
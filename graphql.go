@@ -0,0 +1,190 @@
+package shopify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GRAPHQL_COST_ESTIMATE is the number of query points reserved for a GraphQL
+// call before Shopify reports its actual cost.
+const GRAPHQL_COST_ESTIMATE = 50
+
+// GraphQLError is one entry of a GraphQL response's errors list.
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Locations  []GraphQLLocation      `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Code returns extensions.code, e.g. "THROTTLED" or "ACCESS_DENIED".
+func (e GraphQLError) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return code
+}
+
+// GraphQLErrors is returned by API.GraphQL when the response carries errors.
+// Any data Shopify returned alongside them is still decoded.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Message
+		if code := err.Code(); code != "" {
+			msgs[i] = fmt.Sprintf("%s (%s)", err.Message, code)
+		}
+	}
+	return "shopify: graphql: " + strings.Join(msgs, "; ")
+}
+
+// Is matches ErrRateLimited for throttled queries and ErrForbidden for
+// missing access scopes.
+func (e GraphQLErrors) Is(target error) bool {
+	for _, err := range e {
+		switch err.Code() {
+		case "THROTTLED":
+			if target == ErrRateLimited {
+				return true
+			}
+		case "ACCESS_DENIED":
+			if target == ErrForbidden {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GraphQLUserError is a validation error returned in a mutation payload's
+// userErrors, as opposed to a GraphQLError about the query itself.
+type GraphQLUserError struct {
+	Field   []string `json:"field"`
+	Message string   `json:"message"`
+}
+
+type GraphQLUserErrors []GraphQLUserError
+
+func (e GraphQLUserErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Message
+		if len(err.Field) > 0 {
+			msgs[i] = fmt.Sprintf("%s %s", strings.Join(err.Field, "."), err.Message)
+		}
+	}
+	return "shopify: graphql: " + strings.Join(msgs, "; ")
+}
+
+// Is matches ErrValidation.
+func (e GraphQLUserErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// GraphQLCost is the extensions.cost section of a GraphQL response.
+type GraphQLCost struct {
+	RequestedQueryCost float64               `json:"requestedQueryCost"`
+	ActualQueryCost    float64               `json:"actualQueryCost"`
+	ThrottleStatus     GraphQLThrottleStatus `json:"throttleStatus"`
+}
+
+type GraphQLThrottleStatus struct {
+	MaximumAvailable   float64 `json:"maximumAvailable"`
+	CurrentlyAvailable float64 `json:"currentlyAvailable"`
+	RestoreRate        float64 `json:"restoreRate"`
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data       json.RawMessage `json:"data"`
+	Errors     GraphQLErrors   `json:"errors"`
+	Extensions struct {
+		Cost *GraphQLCost `json:"cost"`
+	} `json:"extensions"`
+}
+
+func (api *API) graphQLEndpoint() string {
+	if api.APIVersion == "" {
+		return "/admin/api/graphql.json"
+	}
+	return fmt.Sprintf("/admin/api/%s/graphql.json", api.APIVersion)
+}
+
+func isGraphQLEndpoint(endpoint string) bool {
+	return strings.HasSuffix(endpoint, "/graphql.json")
+}
+
+// GraphQL runs query against the Admin GraphQL API and decodes its data into
+// out, which may be nil. It shares auth, versioning and the rate limiter with
+// the REST calls: the call waits for enough query points in the shop's cost
+// bucket, and a THROTTLED query is retried once the points it asked for have
+// been restored.
+//
+// The returned cost is nil when Shopify did not report one.
+func (api *API) GraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) (*GraphQLCost, error) {
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return nil, err
+	}
+	payload := buf.Bytes()
+
+	limiter := api.limiter()
+	estimate := float64(GRAPHQL_COST_ESTIMATE)
+
+	for retries := 0; ; retries++ {
+		if err = limiter.WaitCost(ctx, estimate); err != nil {
+			return nil, err
+		}
+
+		res, status, err := api.requestContext(ctx, api.graphQLEndpoint(), "POST", nil, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+
+		if status != 200 {
+			return nil, newResponseError(status, res)
+		}
+
+		r := graphQLResponse{}
+		if err = json.NewDecoder(res).Decode(&r); err != nil {
+			return nil, err
+		}
+
+		cost := r.Extensions.Cost
+		if cost != nil {
+			limiter.UpdateCost(cost.ThrottleStatus)
+			estimate = cost.RequestedQueryCost
+		}
+
+		// A throttled query was not run. The limiter now knows both its cost
+		// and the bucket state, so the next WaitCost sleeps just long enough.
+		if errors.Is(r.Errors, ErrRateLimited) && retries < api.maxRetries() && cost != nil {
+			continue
+		}
+
+		if out != nil && len(r.Data) > 0 && string(r.Data) != "null" {
+			if err = json.Unmarshal(r.Data, out); err != nil {
+				return cost, err
+			}
+		}
+
+		if len(r.Errors) > 0 {
+			return cost, r.Errors
+		}
+		return cost, nil
+	}
+}
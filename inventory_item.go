@@ -0,0 +1,150 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+)
+
+// InventoryItem is the stock-keeping side of a variant, linked through
+// Variant.InventoryItemId. Its quantities are kept per location in
+// InventoryLevels. RequiresShipping and Tracked are pointers so that saving
+// a partly filled item leaves them alone.
+type InventoryItem struct {
+	Cost Money `json:"cost,omitzero"`
+
+	CountryCodeOfOrigin string `json:"country_code_of_origin,omitempty"`
+
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	HarmonizedSystemCode string `json:"harmonized_system_code,omitempty"`
+
+	Id int64 `json:"id,omitempty"`
+
+	ProvinceCodeOfOrigin string `json:"province_code_of_origin,omitempty"`
+
+	RequiresShipping *bool `json:"requires_shipping,omitempty"`
+
+	Sku string `json:"sku,omitempty"`
+
+	Tracked *bool `json:"tracked,omitempty"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
+
+	api *API
+}
+
+type InventoryItemsOptions struct {
+	IDs   string `url:"ids,omitempty"`
+	Limit int    `url:"limit,omitempty"`
+}
+
+func (api *API) InventoryItems(options *InventoryItemsOptions) ([]InventoryItem, error) {
+	return api.InventoryItemsCtx(context.Background(), options)
+}
+
+func (api *API) InventoryItemsCtx(ctx context.Context, options *InventoryItemsOptions) ([]InventoryItem, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/inventory_items.json?%v", qs)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]InventoryItem{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["inventory_items"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = api
+	}
+
+	return result, nil
+}
+
+func (api *API) InventoryItem(id int64) (*InventoryItem, error) {
+	return api.InventoryItemCtx(context.Background(), id)
+}
+
+func (api *API) InventoryItemCtx(ctx context.Context, id int64) (*InventoryItem, error) {
+	endpoint := fmt.Sprintf("/admin/inventory_items/%d.json", id)
+
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]InventoryItem{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["inventory_item"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+// Save updates the item. Inventory items are created and deleted along with
+// their variant.
+func (obj *InventoryItem) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *InventoryItem) SaveCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/inventory_items/%d.json", obj.Id)
+
+	body := map[string]*InventoryItem{}
+	body["inventory_item"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "PUT", nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != 200 {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]InventoryItem{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["inventory_item"]
+	obj.api = api
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+
+	"maps"
+
+	"slices"
+
+	"strconv"
+
+	"strings"
+
+	"time"
+)
+
+// INVENTORY_LEVELS_BATCH is the number of inventory items Shopify accepts in
+// one inventory_levels.json query.
+const INVENTORY_LEVELS_BATCH = 50
+
+// InventoryLevel is the quantity of an InventoryItem available at a Location.
+type InventoryLevel struct {
+	Available int64 `json:"available"`
+
+	InventoryItemId int64 `json:"inventory_item_id"`
+
+	LocationId int64 `json:"location_id"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
+
+	api *API
+}
+
+type InventoryLevelsOptions struct {
+	InventoryItemIDs string    `url:"inventory_item_ids,omitempty"`
+	LocationIDs      string    `url:"location_ids,omitempty"`
+	Limit            int       `url:"limit,omitempty"`
+	UpdatedAtMin     time.Time `url:"updated_at_min,omitempty"`
+}
+
+func (api *API) InventoryLevels(options *InventoryLevelsOptions) ([]InventoryLevel, error) {
+	return api.InventoryLevelsCtx(context.Background(), options)
+}
+
+func (api *API) InventoryLevelsCtx(ctx context.Context, options *InventoryLevelsOptions) ([]InventoryLevel, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/inventory_levels.json?%v", qs)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]InventoryLevel{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["inventory_levels"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = api
+	}
+
+	return result, nil
+}
+
+// InventoryLevelsIter walks every page of inventory levels.
+func (api *API) InventoryLevelsIter(ctx context.Context, options *InventoryLevelsOptions) *Iterator[InventoryLevel] {
+	return newIterator(ctx, api, "/admin/inventory_levels.json", "inventory_levels", options,
+		nil,
+		func(v *InventoryLevel) { v.api = api })
+}
+
+// InventoryLevels lists the stock kept at the location.
+func (obj *Location) InventoryLevels(options *InventoryLevelsOptions) ([]InventoryLevel, error) {
+	return obj.InventoryLevelsCtx(context.Background(), options)
+}
+
+func (obj *Location) InventoryLevelsCtx(ctx context.Context, options *InventoryLevelsOptions) ([]InventoryLevel, error) {
+	o := InventoryLevelsOptions{}
+	if options != nil {
+		o = *options
+	}
+	o.LocationIDs = strconv.FormatInt(obj.Id, 10)
+	return obj.api.InventoryLevelsCtx(ctx, &o)
+}
+
+// InventoryLevel returns the level of inventoryItemId at the location,
+// without fetching it, to adjust, set, connect or delete it.
+func (obj *Location) InventoryLevel(inventoryItemId int64) *InventoryLevel {
+	return &InventoryLevel{InventoryItemId: inventoryItemId, LocationId: obj.Id, api: obj.api}
+}
+
+// Adjust adds delta, which may be negative, to the available quantity.
+func (obj *InventoryLevel) Adjust(delta int64) error {
+	return obj.AdjustCtx(context.Background(), delta)
+}
+
+func (obj *InventoryLevel) AdjustCtx(ctx context.Context, delta int64) error {
+	body := map[string]interface{}{
+		"location_id":          obj.LocationId,
+		"inventory_item_id":    obj.InventoryItemId,
+		"available_adjustment": delta,
+	}
+	return obj.post(ctx, "adjust", 200, body)
+}
+
+// Set replaces the available quantity, connecting the item to the location
+// first if needed.
+func (obj *InventoryLevel) Set(available int64) error {
+	return obj.SetCtx(context.Background(), available)
+}
+
+func (obj *InventoryLevel) SetCtx(ctx context.Context, available int64) error {
+	body := map[string]interface{}{
+		"location_id":       obj.LocationId,
+		"inventory_item_id": obj.InventoryItemId,
+		"available":         available,
+	}
+	return obj.post(ctx, "set", 200, body)
+}
+
+// Connect stocks the item at the location, with nothing available.
+func (obj *InventoryLevel) Connect() error {
+	return obj.ConnectCtx(context.Background())
+}
+
+func (obj *InventoryLevel) ConnectCtx(ctx context.Context) error {
+	body := map[string]interface{}{
+		"location_id":       obj.LocationId,
+		"inventory_item_id": obj.InventoryItemId,
+	}
+	return obj.post(ctx, "connect", 201, body)
+}
+
+func (obj *InventoryLevel) post(ctx context.Context, action string, expectedStatus int, body map[string]interface{}) error {
+	endpoint := fmt.Sprintf("/admin/inventory_levels/%s.json", action)
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]InventoryLevel{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	*obj = r["inventory_level"]
+	obj.api = api
+
+	return nil
+}
+
+// Delete disconnects the item from the location, removing its stock there.
+func (obj *InventoryLevel) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *InventoryLevel) DeleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/inventory_levels.json?inventory_item_id=%d&location_id=%d", obj.InventoryItemId, obj.LocationId)
+	method := "DELETE"
+	expectedStatus := 204
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	return nil
+}
+
+// SyncInventory brings stock, a map of SKU to location id to available
+// quantity, into Shopify with as few calls as possible: levels already at the
+// right quantity are left alone, others get a single adjust call, and
+// locations the item isn't stocked at yet are set directly. It returns the
+// levels it changed.
+//
+// Every SKU must match exactly one inventory item; nothing is changed
+// otherwise.
+func (api *API) SyncInventory(ctx context.Context, stock map[string]map[int64]int64) ([]InventoryLevel, error) {
+	skus := []string{}
+	for sku := range stock {
+		skus = append(skus, sku)
+	}
+	slices.Sort(skus)
+
+	variants, err := api.FindVariantsBySKU(ctx, skus...)
+	if err != nil {
+		return nil, err
+	}
+
+	items := map[string]int64{}
+	for _, v := range variants {
+		if id, ok := items[v.Sku]; ok && id != v.InventoryItemId {
+			return nil, fmt.Errorf("shopify: SKU %q matches several inventory items", v.Sku)
+		}
+		items[v.Sku] = v.InventoryItemId
+	}
+
+	itemIDs := []string{}
+	locationIDs := map[string]bool{}
+	for _, sku := range skus {
+		if items[sku] == 0 {
+			return nil, fmt.Errorf("shopify: no variant with SKU %q", sku)
+		}
+		itemIDs = append(itemIDs, strconv.FormatInt(items[sku], 10))
+		for location := range stock[sku] {
+			locationIDs[strconv.FormatInt(location, 10)] = true
+		}
+	}
+
+	locationQuery := strings.Join(slices.Sorted(maps.Keys(locationIDs)), ",")
+	current := map[[2]int64]int64{}
+	for batch := range slices.Chunk(itemIDs, INVENTORY_LEVELS_BATCH) {
+		it := api.InventoryLevelsIter(ctx, &InventoryLevelsOptions{
+			InventoryItemIDs: strings.Join(batch, ","),
+			LocationIDs:      locationQuery,
+			Limit:            250,
+		})
+		for it.Next() {
+			level := it.Value()
+			current[[2]int64{level.InventoryItemId, level.LocationId}] = level.Available
+		}
+		if err = it.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	result := []InventoryLevel{}
+	for _, sku := range skus {
+		locations := []int64{}
+		for location := range stock[sku] {
+			locations = append(locations, location)
+		}
+		slices.Sort(locations)
+
+		for _, location := range locations {
+			level := &InventoryLevel{InventoryItemId: items[sku], LocationId: location, api: api}
+			available, ok := current[[2]int64{level.InventoryItemId, location}]
+
+			switch {
+			case !ok:
+				err = level.SetCtx(ctx, stock[sku][location])
+			case available != stock[sku][location]:
+				err = level.AdjustCtx(ctx, stock[sku][location]-available)
+			default:
+				continue
+			}
+
+			if err != nil {
+				return result, fmt.Errorf("shopify: updating %s at location %d: %w", sku, location, err)
+			}
+			result = append(result, *level)
+		}
+	}
+
+	return result, nil
+}
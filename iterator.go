@@ -0,0 +1,236 @@
+package shopify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/go-querystring/query"
+	"iter"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const defaultPageLimit = 50
+
+// ListOptions are the paging parameters accepted by list endpoints that
+// don't have options of their own.
+type ListOptions struct {
+	Limit   int    `url:"limit,omitempty"`
+	Page    int    `url:"page,omitempty"`
+	SinceID int64  `url:"since_id,omitempty"`
+	Fields  string `url:"fields,omitempty"`
+}
+
+// Iterator walks every page of a list endpoint, fetching the next page once
+// the current one is used up:
+//
+//	it := api.ProductsIter(ctx, nil)
+//	for it.Next() {
+//		product := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Pages are followed through the Link header's page_info cursor when Shopify
+// sends one, otherwise through since_id for resources that support it, and
+// by page number as a last resort.
+type Iterator[T any] struct {
+	ctx   context.Context
+	api   *API
+	path  string
+	root  string
+	query url.Values
+	limit int
+
+	id   func(*T) int64 // nil when the endpoint doesn't take since_id
+	bind func(*T)
+
+	page []*T
+	cur  *T
+	last bool
+	err  error
+}
+
+func newIterator[T any](ctx context.Context, api *API, path, root string, options interface{}, id func(*T) int64, bind func(*T)) *Iterator[T] {
+	q := url.Values{}
+	var err error
+	if options != nil {
+		if q, err = query.Values(options); err != nil {
+			q = url.Values{}
+		}
+	}
+
+	// since_id orders results by id, which keeps walking the pages stable
+	// for endpoints that would otherwise sort by e.g. creation date.
+	if id != nil && q.Get("since_id") == "" && q.Get("page") == "" {
+		q.Set("since_id", "0")
+	}
+
+	// The next since_id is read from the last value of each page, so it
+	// needs the id even when the caller only asked for other fields.
+	if fields := q.Get("fields"); id != nil && fields != "" {
+		names := strings.Split(fields, ",")
+		for i := range names {
+			names[i] = strings.TrimSpace(names[i])
+		}
+		if !slices.Contains(names, "id") {
+			q.Set("fields", fields+",id")
+		}
+	}
+
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+
+	return &Iterator[T]{
+		ctx:   ctx,
+		api:   api,
+		path:  path,
+		root:  root,
+		query: q,
+		limit: limit,
+		id:    id,
+		bind:  bind,
+		err:   err,
+	}
+}
+
+// Next advances to the next value, fetching another page when needed. It
+// returns false once every page has been read or an error occurred.
+func (it *Iterator[T]) Next() bool {
+	for len(it.page) == 0 {
+		if it.last || it.err != nil {
+			it.cur = nil
+			return false
+		}
+		it.fetch()
+	}
+
+	it.cur = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Value returns the value Next advanced to.
+func (it *Iterator[T]) Value() *T {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All adapts the iterator for range-over-func. An error ends the sequence
+// as a final (nil, err) pair.
+func (it *Iterator[T]) All() iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for it.Next() {
+			if !yield(it.Value(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func (it *Iterator[T]) fetch() {
+	endpoint := fmt.Sprintf("%s?%v", it.path, it.query.Encode())
+	res, status, header, err := it.api.requestHeader(it.ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	if status != 200 {
+		it.err = newResponseError(status, res)
+		return
+	}
+
+	r := map[string][]*T{}
+	if err = json.NewDecoder(res).Decode(&r); err != nil {
+		it.err = err
+		return
+	}
+
+	page := r[it.root]
+	for _, v := range page {
+		it.bind(v)
+	}
+	it.page = page
+
+	if link := header.Get("Link"); link != "" {
+		// Cursor pagination only accepts limit and fields next to page_info.
+		next := parseLinkPageInfo(link, "next")
+		if next == "" {
+			it.last = true
+			return
+		}
+		q := url.Values{"page_info": {next}, "limit": {strconv.Itoa(it.limit)}}
+		if fields := it.query.Get("fields"); fields != "" {
+			q.Set("fields", fields)
+		}
+		it.query = q
+		return
+	}
+
+	if len(page) < it.limit {
+		it.last = true
+		return
+	}
+
+	if it.id != nil && it.query.Get("page") == "" {
+		since, _ := strconv.ParseInt(it.query.Get("since_id"), 10, 64)
+		next := it.id(page[len(page)-1])
+		if next <= since {
+			it.err = fmt.Errorf("shopify: %s: since_id pagination stopped advancing at %d", it.path, since)
+			return
+		}
+		it.query.Set("since_id", strconv.FormatInt(next, 10))
+		return
+	}
+
+	n, _ := strconv.Atoi(it.query.Get("page"))
+	if n == 0 {
+		n = 1
+	}
+	it.query.Set("page", strconv.Itoa(n+1))
+}
+
+// parseLinkPageInfo returns the page_info cursor of the rel link in a Link
+// header such as:
+//
+//	<https://shop.myshopify.com/admin/products.json?limit=50&page_info=abc>; rel="next"
+func parseLinkPageInfo(header, rel string) string {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+
+		match := false
+		for _, p := range parts[1:] {
+			if strings.TrimSpace(p) == fmt.Sprintf(`rel="%s"`, rel) {
+				match = true
+			}
+		}
+		if !match {
+			continue
+		}
+
+		raw := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+		u, err := url.Parse(raw)
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("page_info")
+	}
+	return ""
+}
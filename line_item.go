@@ -1,43 +1,49 @@
 package shopify
 
-import (
-	"time"
-)
-
 type LineItem struct {
-	AppliedDiscounts []interface{} `json:"applied_discounts"`
+	AppliedDiscount *AppliedDiscount `json:"applied_discount,omitempty"`
+
+	AppliedDiscounts []AppliedDiscount `json:"applied_discounts,omitempty"`
+
+	CompareAtPrice Money `json:"compare_at_price,omitzero"`
+
+	FulfillableQuantity int64 `json:"fulfillable_quantity,omitempty"`
+
+	FulfillmentService string `json:"fulfillment_service,omitempty"`
+
+	FulfillmentStatus string `json:"fulfillment_status,omitempty"`
 
-	CompareAtPrice string `json:"compare_at_price"`
+	GiftCard bool `json:"gift_card,omitempty"`
 
-	FulfillmentService string `json:"fulfillment_service"`
+	Grams int64 `json:"grams,omitempty"`
 
-	GiftCard bool `json:"gift_card"`
+	Id int64 `json:"id,omitempty"`
 
-	Grams int64 `json:"grams"`
+	LinePrice Money `json:"line_price,omitzero"`
 
-	LinePrice time.Time `json:"line_price"`
+	Price Money `json:"price,omitzero"`
 
-	Price string `json:"price"`
+	ProductId int64 `json:"product_id,omitempty"`
 
-	ProductId int64 `json:"product_id"`
+	Name string `json:"name,omitempty"`
 
-	Properties string `json:"properties"`
+	Properties []NoteAttribute `json:"properties,omitempty"`
 
-	Quantity int64 `json:"quantity"`
+	Quantity int64 `json:"quantity,omitempty"`
 
-	RequiresShipping bool `json:"requires_shipping"`
+	RequiresShipping *bool `json:"requires_shipping,omitempty"`
 
-	Sku string `json:"sku"`
+	Sku string `json:"sku,omitempty"`
 
-	TaxLines []interface{} `json:"tax_lines"`
+	TaxLines []interface{} `json:"tax_lines,omitempty"`
 
-	Taxable bool `json:"taxable"`
+	Taxable *bool `json:"taxable,omitempty"`
 
-	Title string `json:"title"`
+	Title string `json:"title,omitempty"`
 
-	VariantId int64 `json:"variant_id"`
+	VariantId int64 `json:"variant_id,omitempty"`
 
-	VariantTitle string `json:"variant_title"`
+	VariantTitle string `json:"variant_title,omitempty"`
 
-	Vendor string `json:"vendor"`
+	Vendor string `json:"vendor,omitempty"`
 }
@@ -1,11 +1,11 @@
 package shopify
 
 import (
+	"context"
+
 	"encoding/json"
 
 	"fmt"
-
-	"time"
 )
 
 type Location struct {
@@ -17,9 +17,9 @@ type Location struct {
 
 	Country string `json:"country"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
-	DeletedAt string `json:"deleted_at"`
+	DeletedAt Timestamp `json:"deleted_at,omitzero"`
 
 	Id int64 `json:"id"`
 
@@ -31,7 +31,7 @@ type Location struct {
 
 	Province string `json:"province"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
 	Zip string `json:"zip"`
 
@@ -45,14 +45,18 @@ type Location struct {
 }
 
 func (api *API) Locations() ([]Location, error) {
-	res, status, err := api.request("/admin/locations.json", "GET", nil, nil)
+	return api.LocationsCtx(context.Background())
+}
+
+func (api *API) LocationsCtx(ctx context.Context) ([]Location, error) {
+	res, status, err := api.requestContext(ctx, "/admin/locations.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Location{}
@@ -64,24 +68,28 @@ func (api *API) Locations() ([]Location, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
 func (api *API) Location(id int64) (*Location, error) {
+	return api.LocationCtx(context.Background(), id)
+}
+
+func (api *API) LocationCtx(ctx context.Context, id int64) (*Location, error) {
 	endpoint := fmt.Sprintf("/admin/locations/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Location{}
@@ -3,21 +3,21 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
-
-	"time"
 )
 
 type Metafield struct {
-	CreatedAt     time.Time `json:"created_at"`
+	CreatedAt     Timestamp `json:"created_at,omitzero"`
 	Description   string    `json:"description"`
 	Id            int64     `json:"id"`
 	Key           string    `json:"key"`
 	Namespace     string    `json:"namespace"`
 	OwnerId       int64     `json:"owner_id"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	UpdatedAt     Timestamp `json:"updated_at,omitzero"`
 	Value         string    `json:"value"`
 	ValueType     string    `json:"value_type"`
 	OwnerResource string    `json:"owner_resource"`
@@ -25,14 +25,18 @@ type Metafield struct {
 }
 
 func (api *API) Metafields() ([]*Metafield, error) {
-	res, status, err := api.request("/admin/metafields.json", "GET", nil, nil)
+	return api.MetafieldsCtx(context.Background())
+}
+
+func (api *API) MetafieldsCtx(ctx context.Context) ([]*Metafield, error) {
+	res, status, err := api.requestContext(ctx, "/admin/metafields.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string][]*Metafield{}
@@ -51,17 +55,28 @@ func (api *API) Metafields() ([]*Metafield, error) {
 	return result, nil
 }
 
+// MetafieldsIter walks every page of metafields.
+func (api *API) MetafieldsIter(ctx context.Context, options *ListOptions) *Iterator[Metafield] {
+	return newIterator(ctx, api, "/admin/metafields.json", "metafields", options,
+		func(v *Metafield) int64 { return v.Id },
+		func(v *Metafield) { v.api = api })
+}
+
 func (api *API) Metafield(id int64) (*Metafield, error) {
+	return api.MetafieldCtx(context.Background(), id)
+}
+
+func (api *API) MetafieldCtx(ctx context.Context, id int64) (*Metafield, error) {
 	endpoint := fmt.Sprintf("/admin/metafields/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Metafield{}
@@ -83,9 +98,13 @@ func (api *API) NewMetafield() *Metafield {
 }
 
 func (obj *Metafield) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Metafield) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/metafields/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/metafields.json")
@@ -103,20 +122,14 @@ func (obj *Metafield) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Metafield{}
@@ -134,6 +147,10 @@ func (obj *Metafield) Save() error {
 }
 
 func (obj *Metafield) SaveForProduct(productId int64) error {
+	return obj.SaveForProductCtx(context.Background(), productId)
+}
+
+func (obj *Metafield) SaveForProductCtx(ctx context.Context, productId int64) error {
 	endpoint := fmt.Sprintf("/admin/products/%d/metafields/%d.json", productId, obj.Id)
 	method := "PUT"
 	expectedStatus := 200
@@ -154,20 +171,14 @@ func (obj *Metafield) SaveForProduct(productId int64) error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Metafield{}
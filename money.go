@@ -0,0 +1,275 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact decimal amount, optionally tagged with an ISO 4217
+// currency code. Shopify sends amounts as strings such as "19.99"; Money keeps
+// every digit instead of rounding through a float.
+//
+// The zero value is an unset amount, which marshals to null and is left out
+// of requests by `omitzero`. Arithmetic panics when mixing two different
+// non-empty currencies, and returns ErrMoneyOverflow when a result doesn't fit
+// in an int64 at its scale.
+type Money struct {
+	units int64 // amount in units of 10^-scale
+	scale int32
+	set   bool
+
+	Currency string
+}
+
+// maxMoneyScale bounds the number of decimals so scaled amounts fit an int64.
+const maxMoneyScale = 9
+
+// NewMoney returns units × 10^-scale, e.g. NewMoney(1999, 2, "USD") is 19.99 USD.
+func NewMoney(units int64, scale int, currency string) Money {
+	return Money{units: units, scale: int32(scale), set: true, Currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "19.99" or "-0.5".
+func ParseMoney(s string, currency string) (Money, error) {
+	str := strings.TrimSpace(s)
+	neg := false
+	switch {
+	case strings.HasPrefix(str, "-"):
+		neg = true
+		str = str[1:]
+	case strings.HasPrefix(str, "+"):
+		str = str[1:]
+	}
+
+	whole, frac, _ := strings.Cut(str, ".")
+	if whole == "" && frac == "" || strings.ContainsAny(whole+frac, "+-") {
+		return Money{}, fmt.Errorf("shopify: invalid money amount %q", s)
+	}
+	if len(frac) > maxMoneyScale {
+		return Money{}, fmt.Errorf("shopify: money amount %q has more than %d decimals", s, maxMoneyScale)
+	}
+
+	digits := whole + frac
+	if digits == "" {
+		digits = "0"
+	}
+	units, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("shopify: invalid money amount %q", s)
+	}
+	if neg {
+		units = -units
+	}
+	return NewMoney(units, len(frac), currency), nil
+}
+
+// MustParseMoney is ParseMoney that panics on invalid input, for literals.
+func MustParseMoney(s string, currency string) Money {
+	m, err := ParseMoney(s, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// IsZero reports whether the amount is unset. Use Sign to test for an amount
+// of zero.
+func (m Money) IsZero() bool {
+	return !m.set
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount.
+func (m Money) Sign() int {
+	switch {
+	case m.units < 0:
+		return -1
+	case m.units > 0:
+		return 1
+	}
+	return 0
+}
+
+// String formats the amount with the decimals it was given, e.g. "19.90".
+func (m Money) String() string {
+	if !m.set {
+		return ""
+	}
+
+	units := m.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	digits := strconv.FormatInt(units, 10)
+	if m.scale == 0 {
+		return sign + digits
+	}
+	if pad := int(m.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	i := len(digits) - int(m.scale)
+	return sign + digits[:i] + "." + digits[i:]
+}
+
+// WithCurrency returns the same amount tagged with currency.
+func (m Money) WithCurrency(currency string) Money {
+	m.Currency = currency
+	return m
+}
+
+// ErrMoneyOverflow is returned by arithmetic whose result doesn't fit a
+// Money, e.g. adding amounts with nine decimals past 9,223,372,036.
+var ErrMoneyOverflow = errors.New("shopify: money amount overflows")
+
+func (m Money) Add(o Money) (Money, error) {
+	a, b, currency, err := align(m, o)
+	if err != nil {
+		return Money{}, err
+	}
+	sum := a.units + b.units
+	if (b.units > 0 && sum < a.units) || (b.units < 0 && sum > a.units) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{units: sum, scale: a.scale, set: true, Currency: currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if o.units == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+	return m.Add(o.Neg())
+}
+
+func (m Money) Neg() Money {
+	m.units = -m.units
+	m.set = true
+	return m
+}
+
+// Mul multiplies the amount by n, e.g. a unit price by a quantity.
+func (m Money) Mul(n int64) (Money, error) {
+	product := m.units * n
+	if m.units != 0 && (product/m.units != n || (m.units == -1 && n == math.MinInt64)) {
+		return Money{}, ErrMoneyOverflow
+	}
+	m.units = product
+	m.set = true
+	return m, nil
+}
+
+// Cmp compares two amounts, returning -1, 0 or +1.
+func (m Money) Cmp(o Money) int {
+	a, b, _, err := align(m, o)
+	if err != nil {
+		// Too large to rescale in an int64, so compare exactly in big.Int.
+		x, y := big.NewInt(m.units), big.NewInt(o.units)
+		for scale := m.scale; scale < o.scale; scale++ {
+			x.Mul(x, big.NewInt(10))
+		}
+		for scale := o.scale; scale < m.scale; scale++ {
+			y.Mul(y, big.NewInt(10))
+		}
+		return x.Cmp(y)
+	}
+	switch {
+	case a.units < b.units:
+		return -1
+	case a.units > b.units:
+		return 1
+	}
+	return 0
+}
+
+// Equal reports whether both amounts and currencies are the same, ignoring
+// trailing zeros: 19.9 equals 19.90.
+func (m Money) Equal(o Money) bool {
+	return m.Currency == o.Currency && m.set == o.set && m.Cmp(o) == 0
+}
+
+// align rescales a and b to the larger of their scales, failing with
+// ErrMoneyOverflow if that doesn't fit.
+func align(a, b Money) (Money, Money, string, error) {
+	currency := a.Currency
+	if currency == "" {
+		currency = b.Currency
+	} else if b.Currency != "" && b.Currency != currency {
+		panic(fmt.Sprintf("shopify: mixing money in %s and %s", a.Currency, b.Currency))
+	}
+
+	for a.scale < b.scale {
+		if a.units > math.MaxInt64/10 || a.units < math.MinInt64/10 {
+			return a, b, currency, ErrMoneyOverflow
+		}
+		a.units *= 10
+		a.scale++
+	}
+	for b.scale < a.scale {
+		if b.units > math.MaxInt64/10 || b.units < math.MinInt64/10 {
+			return a, b, currency, ErrMoneyOverflow
+		}
+		b.units *= 10
+		b.scale++
+	}
+	return a, b, currency, nil
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	if !m.set {
+		return []byte("null"), nil
+	}
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts the amount as a string or a number, or as a money
+// object such as a price set's {"amount": "19.99", "currency_code": "USD"}.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || bytes.Equal(b, []byte("null")) {
+		*m = Money{}
+		return nil
+	}
+
+	if len(b) > 0 && b[0] == '{' {
+		obj := struct {
+			Amount          json.RawMessage `json:"amount"`
+			CurrencyCode    string          `json:"currency_code"`
+			GQLCurrencyCode string          `json:"currencyCode"`
+		}{}
+		if err := json.Unmarshal(b, &obj); err != nil {
+			return err
+		}
+		if err := m.UnmarshalJSON(obj.Amount); err != nil {
+			return err
+		}
+		m.Currency = obj.CurrencyCode
+		if m.Currency == "" {
+			m.Currency = obj.GQLCurrencyCode
+		}
+		return nil
+	}
+
+	str := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &str); err != nil {
+			return err
+		}
+		if str == "" {
+			*m = Money{}
+			return nil
+		}
+	}
+
+	parsed, err := ParseMoney(str, m.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
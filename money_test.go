@@ -0,0 +1,117 @@
+package shopify
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := map[string]string{
+		"19.99":  "19.99",
+		"19.90":  "19.90",
+		"-0.5":   "-0.5",
+		"0.05":   "0.05",
+		"100":    "100",
+		".5":     "0.5",
+		"+1.001": "1.001",
+	}
+	for in, expected := range cases {
+		m, err := ParseMoney(in, "")
+		if err != nil {
+			t.Errorf("Unexpected error parsing %q: %v", in, err)
+			continue
+		}
+		if m.String() != expected {
+			t.Errorf("Expected %q to format as %q, got %q", in, expected, m.String())
+		}
+	}
+
+	for _, in := range []string{"", "abc", "1.2.3", "--1", "1.0123456789"} {
+		if _, err := ParseMoney(in, ""); err == nil {
+			t.Errorf("Expected error parsing %q", in)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	price := MustParseMoney("19.99", "USD")
+	total, err := price.Mul(3)
+	if err == nil {
+		total, err = total.Add(MustParseMoney("0.5", "USD"))
+	}
+	if err == nil {
+		total, err = total.Sub(MustParseMoney("10", ""))
+	}
+
+	if err != nil || total.String() != "50.47" || total.Currency != "USD" {
+		t.Errorf("Expected 50.47 USD, got %s %s", total, total.Currency)
+	}
+	if !MustParseMoney("19.9", "").Equal(MustParseMoney("19.90", "")) {
+		t.Errorf("Expected 19.9 to equal 19.90")
+	}
+	if MustParseMoney("0.10", "").Cmp(MustParseMoney("0.09", "")) != 1 {
+		t.Errorf("Expected 0.10 > 0.09")
+	}
+
+	big := MustParseMoney("9223372036.854775807", "")
+	if _, err := big.Add(MustParseMoney("1", "")); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("Expected adding past int64 to overflow, got %v", err)
+	}
+	if _, err := MustParseMoney("1", "").Add(MustParseMoney("0.000000001", "")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := MustParseMoney("10000000000", "").Add(MustParseMoney("0.000000001", "")); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("Expected rescaling past int64 to overflow, got %v", err)
+	}
+	if _, err := big.Mul(2); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("Expected multiplying past int64 to overflow, got %v", err)
+	}
+	if MustParseMoney("10000000000", "").Cmp(MustParseMoney("0.000000001", "")) != 1 {
+		t.Errorf("Expected amounts too large to rescale to still compare")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected mixing currencies to panic")
+		}
+	}()
+	MustParseMoney("1", "USD").Add(MustParseMoney("1", "EUR"))
+}
+
+func TestMoneyJSON(t *testing.T) {
+	order := Order{}
+	var b []byte
+	err := json.Unmarshal([]byte(`{"total_price": "199.00", "subtotal_price": 10.5, "total_tax": null, "total_discounts": {"amount": "5.00", "currency_code": "CAD"}}`), &order)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if order.TotalPrice.String() != "199.00" || order.SubtotalPrice.String() != "10.5" {
+		t.Errorf("Unexpected prices %s, %s", order.TotalPrice, order.SubtotalPrice)
+	}
+	if !order.TotalTax.IsZero() {
+		t.Errorf("Expected null total_tax to be unset")
+	}
+	if order.TotalDiscounts.String() != "5.00" || order.TotalDiscounts.Currency != "CAD" {
+		t.Errorf("Unexpected discounts %s %s", order.TotalDiscounts, order.TotalDiscounts.Currency)
+	}
+
+	b, _ = json.Marshal(Order{Id: 1})
+	for _, field := range []string{"total_price", "closed_at", "created_at", "total_spent"} {
+		if strings.Contains(string(b), field) {
+			t.Errorf("Expected unset %s to be left out, got %s", field, b)
+		}
+	}
+
+	b, _ = json.Marshal(Variant{Price: MustParseMoney("9.50", "")})
+	if string(b) != `{"price":"9.50"}` {
+		t.Errorf("Expected only the price to be sent, got %s", b)
+	}
+
+	taxable, stock := false, int64(0)
+	b, _ = json.Marshal(Variant{Taxable: &taxable, InventoryQuantity: &stock})
+	if string(b) != `{"inventory_quantity":0,"taxable":false}` {
+		t.Errorf("Expected set false and 0 to be sent, got %s", b)
+	}
+}
@@ -0,0 +1,67 @@
+package shopify
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ClientOption configures how an API or App reaches Shopify, e.g.
+//
+//	api := shopify.NewAPI(shop, token, shopify.WithHTTPClient(client))
+type ClientOption func(*clientConfig)
+
+// clientConfig is shared by API and App so every ClientOption applies to both.
+type clientConfig struct {
+	client    *http.Client
+	baseURL   string
+	userAgent string
+}
+
+// WithHTTPClient sends requests through c instead of http.DefaultClient, for
+// proxies, custom TLS or transports that record traffic.
+func WithHTTPClient(c *http.Client) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.client = c
+	}
+}
+
+// WithBaseURL sends requests to u instead of https://{shop}, e.g. to point
+// the package at an httptest.Server.
+func WithBaseURL(u string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.baseURL = strings.TrimRight(u, "/")
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.userAgent = ua
+	}
+}
+
+func (cfg *clientConfig) apply(opts []ClientOption) {
+	for _, opt := range opts {
+		opt(cfg)
+	}
+}
+
+func (cfg *clientConfig) httpClient() *http.Client {
+	if cfg.client != nil {
+		return cfg.client
+	}
+	return http.DefaultClient
+}
+
+func (cfg *clientConfig) shopURL(shop string) string {
+	if cfg.baseURL != "" {
+		return cfg.baseURL
+	}
+	return "https://" + shop
+}
+
+func (cfg *clientConfig) setHeaders(req *http.Request) {
+	if cfg.userAgent != "" {
+		req.Header.Set("User-Agent", cfg.userAgent)
+	}
+}
@@ -3,6 +3,8 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
@@ -15,17 +17,17 @@ type Order struct {
 
 	CancelReason string `json:"cancel_reason"`
 
-	CancelledAt string `json:"cancelled_at"`
+	CancelledAt Timestamp `json:"cancelled_at,omitzero"`
 
 	CartToken string `json:"cart_token"`
 
 	CheckoutToken string `json:"checkout_token"`
 
-	ClosedAt string `json:"closed_at"`
+	ClosedAt Timestamp `json:"closed_at,omitzero"`
 
 	Confirmed bool `json:"confirmed"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
 	Currency string `json:"currency"`
 
@@ -49,7 +51,7 @@ type Order struct {
 
 	Number int64 `json:"number"`
 
-	ProcessedAt time.Time `json:"processed_at"`
+	ProcessedAt Timestamp `json:"processed_at,omitzero"`
 
 	Reference string `json:"reference"`
 
@@ -61,7 +63,7 @@ type Order struct {
 
 	SourceUrl string `json:"source_url"`
 
-	SubtotalPrice time.Time `json:"subtotal_price"`
+	SubtotalPrice Money `json:"subtotal_price,omitzero"`
 
 	TaxesIncluded bool `json:"taxes_included"`
 
@@ -69,19 +71,19 @@ type Order struct {
 
 	Token string `json:"token"`
 
-	TotalDiscounts string `json:"total_discounts"`
+	TotalDiscounts Money `json:"total_discounts,omitzero"`
 
-	TotalLineItemsPrice time.Time `json:"total_line_items_price"`
+	TotalLineItemsPrice Money `json:"total_line_items_price,omitzero"`
 
-	TotalPrice time.Time `json:"total_price"`
+	TotalPrice Money `json:"total_price,omitzero"`
 
-	TotalPriceUsd string `json:"total_price_usd"`
+	TotalPriceUsd Money `json:"total_price_usd,omitzero"`
 
-	TotalTax string `json:"total_tax"`
+	TotalTax Money `json:"total_tax,omitzero"`
 
 	TotalWeight int64 `json:"total_weight"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
 	UserId string `json:"user_id"`
 
@@ -91,7 +93,7 @@ type Order struct {
 
 	OrderNumber int64 `json:"order_number"`
 
-	DiscountCodes []interface{} `json:"discount_codes"`
+	DiscountCodes []OrderDiscountCode `json:"discount_codes"`
 
 	NoteAttributes []interface{} `json:"note_attributes"`
 
@@ -113,26 +115,58 @@ type Order struct {
 
 	ShippingAddress BillingAddress `json:"shipping_address"`
 
-	Fulfillments []interface{} `json:"fulfillments"`
+	Fulfillments []Fulfillment `json:"fulfillments"`
 
 	ClientDetails ClientDetail `json:"client_details"`
 
-	Refunds []interface{} `json:"refunds"`
+	Refunds []Refund `json:"refunds"`
 
 	Customer Customer `json:"customer"`
 
 	api *API
 }
 
-func (api *API) Orders() ([]Order, error) {
-	res, status, err := api.request("/admin/orders.json", "GET", nil, nil)
+// Reasons accepted by Order.Cancel.
+const (
+	OrderCancelCustomer  = "customer"
+	OrderCancelFraud     = "fraud"
+	OrderCancelInventory = "inventory"
+	OrderCancelDeclined  = "declined"
+	OrderCancelOther     = "other"
+)
+
+type OrdersOptions struct {
+	IDs               string    `url:"ids,omitempty"`
+	Limit             int       `url:"limit,omitempty"`
+	Page              int       `url:"page,omitempty"`
+	SinceID           int64     `url:"since_id,omitempty"`
+	Status            string    `url:"status,omitempty"`             // open (default), closed, cancelled or any
+	FinancialStatus   string    `url:"financial_status,omitempty"`   // e.g. paid, pending, refunded, unpaid or any
+	FulfillmentStatus string    `url:"fulfillment_status,omitempty"` // shipped, partial, unshipped, unfulfilled or any
+	CreatedAtMin      time.Time `url:"created_at_min,omitempty"`
+	CreatedAtMax      time.Time `url:"created_at_max,omitempty"`
+	UpdatedAtMin      time.Time `url:"updated_at_min,omitempty"`
+	UpdatedAtMax      time.Time `url:"updated_at_max,omitempty"`
+	ProcessedAtMin    time.Time `url:"processed_at_min,omitempty"`
+	ProcessedAtMax    time.Time `url:"processed_at_max,omitempty"`
+	Fields            string    `url:"fields,omitempty"`
+}
+
+func (api *API) Orders(options *OrdersOptions) ([]Order, error) {
+	return api.OrdersCtx(context.Background(), options)
+}
+
+func (api *API) OrdersCtx(ctx context.Context, options *OrdersOptions) ([]Order, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/orders.json?%v", qs)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Order{}
@@ -144,24 +178,73 @@ func (api *API) Orders() ([]Order, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].bind(api)
 	}
 
 	return result, nil
 }
 
+// OrdersIter walks every page of orders.
+func (api *API) OrdersIter(ctx context.Context, options *OrdersOptions) *Iterator[Order] {
+	return newIterator(ctx, api, "/admin/orders.json", "orders", options,
+		func(v *Order) int64 { return v.Id },
+		func(v *Order) { v.bind(api) })
+}
+
+type OrdersCountOptions struct {
+	Status            string    `url:"status,omitempty"`
+	FinancialStatus   string    `url:"financial_status,omitempty"`
+	FulfillmentStatus string    `url:"fulfillment_status,omitempty"`
+	CreatedAtMin      time.Time `url:"created_at_min,omitempty"`
+	CreatedAtMax      time.Time `url:"created_at_max,omitempty"`
+	UpdatedAtMin      time.Time `url:"updated_at_min,omitempty"`
+	UpdatedAtMax      time.Time `url:"updated_at_max,omitempty"`
+}
+
+func (api *API) OrdersCount(options *OrdersCountOptions) (int, error) {
+	return api.OrdersCountCtx(context.Background(), options)
+}
+
+func (api *API) OrdersCountCtx(ctx context.Context, options *OrdersCountOptions) (int, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/orders/count.json?%v", qs)
+
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status != 200 {
+		return 0, newResponseError(status, res)
+	}
+
+	r := map[string]int{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return r["count"], nil
+}
+
 func (api *API) Order(id int64) (*Order, error) {
+	return api.OrderCtx(context.Background(), id)
+}
+
+func (api *API) OrderCtx(ctx context.Context, id int64) (*Order, error) {
 	endpoint := fmt.Sprintf("/admin/orders/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Order{}
@@ -172,7 +255,7 @@ func (api *API) Order(id int64) (*Order, error) {
 		return nil, err
 	}
 
-	result.api = api
+	result.bind(api)
 
 	return &result, nil
 }
@@ -181,10 +264,26 @@ func (api *API) NewOrder() *Order {
 	return &Order{api: api}
 }
 
+// bind attaches api to the order and its nested fulfillments and refund
+// transactions, so they can be updated on their own.
+func (obj *Order) bind(api *API) {
+	obj.api = api
+	for i := range obj.Fulfillments {
+		obj.Fulfillments[i].api = api
+	}
+	for i := range obj.Refunds {
+		obj.Refunds[i].bind(api)
+	}
+}
+
 func (obj *Order) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Order) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/orders/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/orders.json")
@@ -202,21 +301,152 @@ func (obj *Order) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+		return newResponseError(status, res)
+	}
+
+	r := map[string]Order{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	*obj = r["order"]
+	obj.bind(api)
+
+	return nil
+}
+
+// Cancel cancels the order. With refund set, whatever is left to refund of
+// the order, as calculated by Shopify, goes back through its original
+// gateways; an unpaid or fully refunded order is cancelled without a refund.
+// restock puts the line items back in stock and email notifies the customer.
+func (obj *Order) Cancel(reason string, refund, restock, email bool) error {
+	return obj.CancelCtx(context.Background(), reason, refund, restock, email)
+}
+
+func (obj *Order) CancelCtx(ctx context.Context, reason string, refund, restock, email bool) error {
+	body := map[string]interface{}{
+		"restock": restock,
+		"email":   email,
+	}
+	if reason != "" {
+		body["reason"] = reason
+	}
+	if refund {
+		r, err := obj.cancelRefund(ctx)
+		if err != nil {
+			return err
+		}
+		if r != nil {
+			r.Notify = email
+			body["refund"] = r
+		}
+	}
+
+	return obj.action(ctx, "cancel", body)
+}
+
+// cancelRefund asks Shopify for the refund of everything not refunded yet:
+// the remaining quantity of each line item and the shipping. The order is
+// reloaded first if it was fetched without its line items. It returns nil
+// when no money was captured or none is left to refund, so the order is
+// cancelled without a refund.
+func (obj *Order) cancelRefund(ctx context.Context) (*Refund, error) {
+	order := obj
+	if len(order.LineItems) == 0 {
+		var err error
+		if order, err = obj.api.OrderCtx(ctx, obj.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	switch order.FinancialStatus {
+	case "pending", "authorized", "voided", "refunded":
+		return nil, nil
+	}
+
+	refunded := map[int64]int64{}
+	for _, r := range order.Refunds {
+		for _, item := range r.RefundLineItems {
+			refunded[item.LineItemId] += item.Quantity
+		}
+	}
+
+	request := &Refund{Shipping: &RefundShipping{FullRefund: true}}
+	for _, item := range order.LineItems {
+		if quantity := item.Quantity - refunded[item.Id]; quantity > 0 {
+			request.AddLineItem(item.Id, quantity, RestockNone, nil)
+		}
+	}
+
+	result, err := order.CalculateRefundCtx(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := []Transaction{}
+	for _, t := range result.Transactions {
+		if t.Amount.Sign() > 0 {
+			t.Kind = TransactionRefund
+			transactions = append(transactions, t)
 		}
 	}
+	if len(transactions) == 0 {
+		return nil, nil
+	}
+	result.Transactions = transactions
+
+	return result, nil
+}
+
+func (obj *Order) Close() error {
+	return obj.CloseCtx(context.Background())
+}
+
+func (obj *Order) CloseCtx(ctx context.Context) error {
+	return obj.action(ctx, "close", map[string]interface{}{})
+}
+
+// Open re-opens a closed order.
+func (obj *Order) Open() error {
+	return obj.OpenCtx(context.Background())
+}
+
+func (obj *Order) OpenCtx(ctx context.Context) error {
+	return obj.action(ctx, "open", map[string]interface{}{})
+}
+
+// action POSTs to one of the order's lifecycle endpoints, e.g.
+// /admin/orders/1/close.json, and reloads the order from the response.
+func (obj *Order) action(ctx context.Context, name string, body map[string]interface{}) error {
+	endpoint := fmt.Sprintf("/admin/orders/%d/%s.json", obj.Id, name)
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != 200 {
+		return newResponseError(status, res)
+	}
 
 	r := map[string]Order{}
 	err = json.NewDecoder(res).Decode(&r)
@@ -226,6 +456,29 @@ func (obj *Order) Save() error {
 	}
 
 	*obj = r["order"]
+	obj.bind(api)
+
+	return nil
+}
+
+func (obj *Order) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *Order) DeleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/orders/%d.json", obj.Id)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
 
 	return nil
 }
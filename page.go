@@ -3,11 +3,11 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
-
-	"time"
 )
 
 type Page struct {
@@ -15,13 +15,13 @@ type Page struct {
 
 	BodyHtml string `json:"body_html"`
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
 	Handle string `json:"handle"`
 
 	Id int64 `json:"id"`
 
-	PublishedAt time.Time `json:"published_at"`
+	PublishedAt Timestamp `json:"published_at,omitzero"`
 
 	ShopId int64 `json:"shop_id"`
 
@@ -29,20 +29,24 @@ type Page struct {
 
 	Title string `json:"title"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
 	api *API
 }
 
 func (api *API) Pages() ([]Page, error) {
-	res, status, err := api.request("/admin/pages.json", "GET", nil, nil)
+	return api.PagesCtx(context.Background())
+}
+
+func (api *API) PagesCtx(ctx context.Context) ([]Page, error) {
+	res, status, err := api.requestContext(ctx, "/admin/pages.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Page{}
@@ -54,24 +58,35 @@ func (api *API) Pages() ([]Page, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
+// PagesIter walks every page of pages.
+func (api *API) PagesIter(ctx context.Context, options *ListOptions) *Iterator[Page] {
+	return newIterator(ctx, api, "/admin/pages.json", "pages", options,
+		func(v *Page) int64 { return v.Id },
+		func(v *Page) { v.api = api })
+}
+
 func (api *API) Page(id int64) (*Page, error) {
+	return api.PageCtx(context.Background(), id)
+}
+
+func (api *API) PageCtx(ctx context.Context, id int64) (*Page, error) {
 	endpoint := fmt.Sprintf("/admin/pages/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Page{}
@@ -93,9 +108,13 @@ func (api *API) NewPage() *Page {
 }
 
 func (obj *Page) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Page) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/pages/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/pages.json")
@@ -113,20 +132,14 @@ func (obj *Page) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Page{}
@@ -136,7 +149,9 @@ func (obj *Page) Save() error {
 		return err
 	}
 
+	api := obj.api
 	*obj = r["page"]
+	obj.api = api
 
 	return nil
 }
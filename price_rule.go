@@ -0,0 +1,255 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+)
+
+// Values of the PriceRule enumerations.
+const (
+	PriceRuleTargetLineItem     = "line_item"
+	PriceRuleTargetShippingLine = "shipping_line"
+
+	PriceRuleSelectionAll          = "all"
+	PriceRuleSelectionEntitled     = "entitled"
+	PriceRuleSelectionPrerequisite = "prerequisite"
+
+	PriceRuleAllocationEach   = "each"
+	PriceRuleAllocationAcross = "across"
+)
+
+// PriceRule describes a discount: what it applies to (the entitled items),
+// the conditions to get it (the prerequisites) and its value. Customers
+// redeem it through its DiscountCodes.
+type PriceRule struct {
+	AllocationLimit int64 `json:"allocation_limit,omitempty"`
+
+	AllocationMethod string `json:"allocation_method,omitempty"`
+
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	CustomerSelection string `json:"customer_selection,omitempty"`
+
+	EndsAt Timestamp `json:"ends_at,omitzero"`
+
+	EntitledCollectionIds []int64 `json:"entitled_collection_ids,omitempty"`
+
+	EntitledCountryIds []int64 `json:"entitled_country_ids,omitempty"`
+
+	EntitledProductIds []int64 `json:"entitled_product_ids,omitempty"`
+
+	EntitledVariantIds []int64 `json:"entitled_variant_ids,omitempty"`
+
+	Id int64 `json:"id,omitempty"`
+
+	OncePerCustomer bool `json:"once_per_customer"`
+
+	PrerequisiteCollectionIds []int64 `json:"prerequisite_collection_ids,omitempty"`
+
+	PrerequisiteCustomerIds []int64 `json:"prerequisite_customer_ids,omitempty"`
+
+	PrerequisiteProductIds []int64 `json:"prerequisite_product_ids,omitempty"`
+
+	PrerequisiteQuantityRange *PriceRuleQuantityRange `json:"prerequisite_quantity_range,omitempty"`
+
+	PrerequisiteSavedSearchIds []int64 `json:"prerequisite_saved_search_ids,omitempty"`
+
+	PrerequisiteShippingPriceRange *PriceRuleMoneyRange `json:"prerequisite_shipping_price_range,omitempty"`
+
+	PrerequisiteSubtotalRange *PriceRuleMoneyRange `json:"prerequisite_subtotal_range,omitempty"`
+
+	PrerequisiteToEntitlementQuantityRatio *PriceRuleQuantityRatio `json:"prerequisite_to_entitlement_quantity_ratio,omitempty"`
+
+	PrerequisiteVariantIds []int64 `json:"prerequisite_variant_ids,omitempty"`
+
+	StartsAt Timestamp `json:"starts_at,omitzero"`
+
+	TargetSelection string `json:"target_selection,omitempty"`
+
+	TargetType string `json:"target_type,omitempty"`
+
+	Title string `json:"title,omitempty"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
+
+	UsageLimit int64 `json:"usage_limit,omitempty"`
+
+	// Value is negative, e.g. "-10.0" for 10% or 10.00 off depending on
+	// ValueType.
+	Value string `json:"value,omitempty"`
+
+	ValueType string `json:"value_type,omitempty"`
+
+	api *API
+}
+
+// PriceRuleMoneyRange bounds a subtotal or shipping price. Only one bound is
+// used at a time.
+type PriceRuleMoneyRange struct {
+	GreaterThanOrEqualTo Money `json:"greater_than_or_equal_to,omitzero"`
+
+	LessThanOrEqualTo Money `json:"less_than_or_equal_to,omitzero"`
+}
+
+type PriceRuleQuantityRange struct {
+	GreaterThanOrEqualTo int64 `json:"greater_than_or_equal_to"`
+}
+
+// PriceRuleQuantityRatio makes a "buy X get Y" discount: EntitledQuantity
+// items are discounted for every PrerequisiteQuantity items bought.
+type PriceRuleQuantityRatio struct {
+	PrerequisiteQuantity int64 `json:"prerequisite_quantity"`
+
+	EntitledQuantity int64 `json:"entitled_quantity"`
+}
+
+func (api *API) PriceRules(options *ListOptions) ([]PriceRule, error) {
+	return api.PriceRulesCtx(context.Background(), options)
+}
+
+func (api *API) PriceRulesCtx(ctx context.Context, options *ListOptions) ([]PriceRule, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/price_rules.json?%v", qs)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]PriceRule{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["price_rules"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = api
+	}
+
+	return result, nil
+}
+
+// PriceRulesIter walks every page of price rules.
+func (api *API) PriceRulesIter(ctx context.Context, options *ListOptions) *Iterator[PriceRule] {
+	return newIterator(ctx, api, "/admin/price_rules.json", "price_rules", options,
+		func(v *PriceRule) int64 { return v.Id },
+		func(v *PriceRule) { v.api = api })
+}
+
+func (api *API) PriceRule(id int64) (*PriceRule, error) {
+	return api.PriceRuleCtx(context.Background(), id)
+}
+
+func (api *API) PriceRuleCtx(ctx context.Context, id int64) (*PriceRule, error) {
+	endpoint := fmt.Sprintf("/admin/price_rules/%d.json", id)
+
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]PriceRule{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["price_rule"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewPriceRule() *PriceRule {
+	return &PriceRule{api: api}
+}
+
+func (obj *PriceRule) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *PriceRule) SaveCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/price_rules/%d.json", obj.Id)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.Id == 0 {
+		endpoint = fmt.Sprintf("/admin/price_rules.json")
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	body := map[string]*PriceRule{}
+	body["price_rule"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]PriceRule{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["price_rule"]
+	obj.api = api
+
+	return nil
+}
+
+// Delete removes the price rule along with its discount codes.
+func (obj *PriceRule) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *PriceRule) DeleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/price_rules/%d.json", obj.Id)
+	method := "DELETE"
+	expectedStatus := 204
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	return nil
+}
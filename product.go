@@ -2,65 +2,71 @@ package shopify
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/go-querystring/query"
 	"strconv"
+	"time"
 )
 
 type Product struct {
-	BodyHtml       string      `json:"body_html,omitempty"`
-	CreatedAt      string      `json:"created_at,omitempty"`
-	Handle         string      `json:"handle,omitempty"`
-	ID             int64       `json:"id,omitempty"`
-	Images         interface{} `json:"images,omitempty"`
-	Options        []Option    `json:"options,omitempty"`
-	ProductType    string      `json:"product_type,omitempty"`
-	PublishedAt    string      `json:"published_at,omitempty"`
-	PublishedScope string      `json:"published_scope,omitempty"`
-	Tags           string      `json:"tags,omitempty"`
-	TemplateSuffix string      `json:"template_suffix,omitempty"`
-	Title          string      `json:"title,omitempty"`
-	UpdatedAt      string      `json:"updated_at,omitempty"`
-	Variants       []Variant   `json:"variants,omitempty"`
-	Vendor         string      `json:"vendor,omitempty"`
+	BodyHtml       string         `json:"body_html,omitempty"`
+	CreatedAt      Timestamp      `json:"created_at,omitzero"`
+	Handle         string         `json:"handle,omitempty"`
+	ID             int64          `json:"id,omitempty"`
+	Images         []ProductImage `json:"images,omitempty"`
+	Options        []Option       `json:"options,omitempty"`
+	ProductType    string         `json:"product_type,omitempty"`
+	PublishedAt    Timestamp      `json:"published_at,omitzero"`
+	PublishedScope string         `json:"published_scope,omitempty"`
+	Tags           string         `json:"tags,omitempty"`
+	TemplateSuffix string         `json:"template_suffix,omitempty"`
+	Title          string         `json:"title,omitempty"`
+	UpdatedAt      Timestamp      `json:"updated_at,omitzero"`
+	Variants       []Variant      `json:"variants,omitempty"`
+	Vendor         string         `json:"vendor,omitempty"`
 
 	api *API
 }
 
 type ProductsOptions struct {
-	IDs             string `url:"ids,omitempty"`
-	Limit           int    `url:"limit,omitempty"`
-	Page            int    `url:"page,omitempty"`
-	SinceID         int64  `url:"since_id,omitempty"`
-	Title           string `url:"title,omitempty"`
-	Vendor          string `url:"vendor,omitempty"`
-	Handle          string `url:"handle,omitempty"`
-	ProductType     string `url:"product_type,omitempty"`
-	CollectionID    string `url:"collection_id,omitempty"`
-	CreatedAtMin    string `url:"created_at_min,omitempty"`
-	CreatedAtMax    string `url:"created_at_max,omitempty"`
-	UpdatedAtMin    string `url:"updated_at_min,omitempty"`
-	UpdatedAtMax    string `url:"updated_at_max,omitempty"`
-	PublishedAtMin  string `url:"published_at_min,omitempty"`
-	PublishedAtMax  string `url:"published_at_max,omitempty"`
-	PublishedStatus string `url:"published_status,omitempty"`
-	Fields          string `url:"fields,omitempty"`
+	IDs             string    `url:"ids,omitempty"`
+	Limit           int       `url:"limit,omitempty"`
+	Page            int       `url:"page,omitempty"`
+	SinceID         int64     `url:"since_id,omitempty"`
+	Title           string    `url:"title,omitempty"`
+	Vendor          string    `url:"vendor,omitempty"`
+	Handle          string    `url:"handle,omitempty"`
+	ProductType     string    `url:"product_type,omitempty"`
+	CollectionID    string    `url:"collection_id,omitempty"`
+	CreatedAtMin    time.Time `url:"created_at_min,omitempty"`
+	CreatedAtMax    time.Time `url:"created_at_max,omitempty"`
+	UpdatedAtMin    time.Time `url:"updated_at_min,omitempty"`
+	UpdatedAtMax    time.Time `url:"updated_at_max,omitempty"`
+	PublishedAtMin  time.Time `url:"published_at_min,omitempty"`
+	PublishedAtMax  time.Time `url:"published_at_max,omitempty"`
+	PublishedStatus string    `url:"published_status,omitempty"`
+	Fields          string    `url:"fields,omitempty"`
 }
 
 func (api *API) Products(options *ProductsOptions) ([]*Product, error) {
+	return api.ProductsCtx(context.Background(), options)
+}
+
+func (api *API) ProductsCtx(ctx context.Context, options *ProductsOptions) ([]*Product, error) {
 
 	qs := encodeOptions(options)
 	endpoint := fmt.Sprintf("/admin/products.json?%v", qs)
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]*Product{}
@@ -71,38 +77,49 @@ func (api *API) Products(options *ProductsOptions) ([]*Product, error) {
 
 	result := (*r)["products"]
 	for _, v := range result {
-		v.api = api
+		v.bind(api)
 	}
 
 	return result, nil
 }
 
+// ProductsIter walks every page of products.
+func (api *API) ProductsIter(ctx context.Context, options *ProductsOptions) *Iterator[Product] {
+	return newIterator(ctx, api, "/admin/products.json", "products", options,
+		func(v *Product) int64 { return v.ID },
+		func(v *Product) { v.bind(api) })
+}
+
 type ProductsCountOptions struct {
-	Vendor          string `url:"vendor,omitempty"`
-	ProductType     string `url:"product_type,omitempty"`
-	CollectionID    string `url:"collection_id,omitempty"`
-	CreatedAtMin    string `url:"created_at_min,omitempty"`
-	CreatedAtMax    string `url:"created_at_max,omitempty"`
-	UpdatedAtMin    string `url:"updated_at_min,omitempty"`
-	UpdatedAtMax    string `url:"updated_at_max,omitempty"`
-	PublishedAtMin  string `url:"published_at_min,omitempty"`
-	PublishedAtMax  string `url:"published_at_max,omitempty"`
-	PublishedStatus string `url:"published_status,omitempty"`
+	Vendor          string    `url:"vendor,omitempty"`
+	ProductType     string    `url:"product_type,omitempty"`
+	CollectionID    string    `url:"collection_id,omitempty"`
+	CreatedAtMin    time.Time `url:"created_at_min,omitempty"`
+	CreatedAtMax    time.Time `url:"created_at_max,omitempty"`
+	UpdatedAtMin    time.Time `url:"updated_at_min,omitempty"`
+	UpdatedAtMax    time.Time `url:"updated_at_max,omitempty"`
+	PublishedAtMin  time.Time `url:"published_at_min,omitempty"`
+	PublishedAtMax  time.Time `url:"published_at_max,omitempty"`
+	PublishedStatus string    `url:"published_status,omitempty"`
 }
 
 func (api *API) ProductsCount(options *ProductsCountOptions) (int, error) {
+	return api.ProductsCountCtx(context.Background(), options)
+}
+
+func (api *API) ProductsCountCtx(ctx context.Context, options *ProductsCountOptions) (int, error) {
 
 	qs := encodeOptions(options)
 	endpoint := fmt.Sprintf("/admin/products/count.json?%v", qs)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return 0, err
 	}
 
 	if status != 200 {
-		return 0, fmt.Errorf("Status returned: %d", status)
+		return 0, newResponseError(status, res)
 	}
 
 	r := map[string]interface{}{}
@@ -116,16 +133,20 @@ func (api *API) ProductsCount(options *ProductsCountOptions) (int, error) {
 }
 
 func (api *API) Product(id int64) (*Product, error) {
+	return api.ProductCtx(context.Background(), id)
+}
+
+func (api *API) ProductCtx(ctx context.Context, id int64) (*Product, error) {
 	endpoint := fmt.Sprintf("/admin/products/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Product{}
@@ -137,7 +158,7 @@ func (api *API) Product(id int64) (*Product, error) {
 		return nil, err
 	}
 
-	result.api = api
+	result.bind(api)
 
 	return &result, nil
 }
@@ -146,33 +167,49 @@ func (api *API) NewProduct() *Product {
 	return &Product{api: api}
 }
 
+// bind attaches api to the product and its nested variants and images, so
+// they can be saved on their own.
+func (obj *Product) bind(api *API) {
+	obj.api = api
+	for i := range obj.Variants {
+		obj.Variants[i].bind(api)
+	}
+	for i := range obj.Images {
+		obj.Images[i].api = api
+	}
+}
+
 type ProductsMetafieldsOptions struct {
-	Limit        int    `url:"limit,omitempty"`
-	SinceID      string `url:"since_id,omitempty"`
-	CreatedAtMin string `url:"created_at_min,omitempty"`
-	CreatedAtMax string `url:"created_at_max,omitempty"`
-	UpdatedAtMin string `url:"updated_at_min,omitempty"`
-	UpdatedAtMax string `url:"updated_at_max,omitempty"`
-	Namepace     string `url:"namepace,omitempty"`
-	Key          string `url:"key,omitempty"`
-	ValueType    string `url:"value_type,omitempty"`
-	Fields       string `url:"fields,omitempty"`
+	Limit        int       `url:"limit,omitempty"`
+	SinceID      string    `url:"since_id,omitempty"`
+	CreatedAtMin time.Time `url:"created_at_min,omitempty"`
+	CreatedAtMax time.Time `url:"created_at_max,omitempty"`
+	UpdatedAtMin time.Time `url:"updated_at_min,omitempty"`
+	UpdatedAtMax time.Time `url:"updated_at_max,omitempty"`
+	Namepace     string    `url:"namepace,omitempty"`
+	Key          string    `url:"key,omitempty"`
+	ValueType    string    `url:"value_type,omitempty"`
+	Fields       string    `url:"fields,omitempty"`
 }
 
 func (obj *Product) Metafields(options *ProductsMetafieldsOptions) ([]*Metafield, error) {
+	return obj.MetafieldsCtx(context.Background(), options)
+}
+
+func (obj *Product) MetafieldsCtx(ctx context.Context, options *ProductsMetafieldsOptions) ([]*Metafield, error) {
 	if obj == nil || obj.api == nil {
 		return nil, errors.New("Product is nil")
 	}
 	qs := encodeOptions(options)
 	endpoint := fmt.Sprintf("/admin/products/%d/metafields.json?%v", obj.ID, qs)
-	res, status, err := obj.api.request(endpoint, "GET", nil, nil)
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string][]*Metafield{}
@@ -191,6 +228,15 @@ func (obj *Product) Metafields(options *ProductsMetafieldsOptions) ([]*Metafield
 	return result, nil
 }
 
+// MetafieldsIter walks every page of the product's metafields.
+func (obj *Product) MetafieldsIter(ctx context.Context, options *ProductsMetafieldsOptions) *Iterator[Metafield] {
+	api := obj.api
+	endpoint := fmt.Sprintf("/admin/products/%d/metafields.json", obj.ID)
+	return newIterator(ctx, api, endpoint, "metafields", options,
+		func(v *Metafield) int64 { return v.Id },
+		func(v *Metafield) { v.api = api })
+}
+
 //func (obj *Product) Save() error {
 //	endpoint := fmt.Sprintf("/admin/products/%d.json", obj.Id)
 //	method := "PUT"
@@ -243,6 +289,10 @@ func (obj *Product) Metafields(options *ProductsMetafieldsOptions) ([]*Metafield
 //}
 
 func (obj *Product) Save(partial *Product) error {
+	return obj.SaveCtx(context.Background(), partial)
+}
+
+func (obj *Product) SaveCtx(ctx context.Context, partial *Product) error {
 	endpoint := fmt.Sprintf("/admin/products/%d.json", obj.ID)
 	method := "PUT"
 	expectedStatus := 200
@@ -267,20 +317,14 @@ func (obj *Product) Save(partial *Product) error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Product{}
@@ -292,30 +336,28 @@ func (obj *Product) Save(partial *Product) error {
 
 	api := obj.api
 	*obj = r["product"]
-	obj.api = api
+	obj.bind(api)
 
 	return nil
 }
 
 func (obj *Product) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *Product) DeleteCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/products/%d.json", obj.ID)
 	method := "DELETE"
 	expectedStatus := 200
 
-	res, status, err := obj.api.request(endpoint, method, nil, nil)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	return nil
@@ -0,0 +1,254 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/base64"
+
+	"encoding/json"
+
+	"fmt"
+
+	"os"
+
+	"path/filepath"
+
+	"strings"
+)
+
+type ProductImage struct {
+	Alt string `json:"alt,omitempty"`
+
+	// Attachment is the base64 encoded image, used instead of Src to upload
+	// an image from local bytes. See Attach.
+	Attachment string `json:"attachment,omitempty"`
+
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	Filename string `json:"filename,omitempty"`
+
+	Height int64 `json:"height,omitempty"`
+
+	Id int64 `json:"id,omitempty"`
+
+	Position int64 `json:"position,omitempty"`
+
+	ProductId int64 `json:"product_id,omitempty"`
+
+	Src string `json:"src,omitempty"`
+
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
+
+	VariantIds []int64 `json:"variant_ids,omitempty"`
+
+	Width int64 `json:"width,omitempty"`
+
+	api *API
+}
+
+// imageExtensions are the files picked up by Product.UploadImages.
+var imageExtensions = map[string]bool{
+	".gif":  true,
+	".jpeg": true,
+	".jpg":  true,
+	".png":  true,
+	".webp": true,
+}
+
+// ListImages fetches the product's images. The Images field holds the ones
+// embedded in the product when it was loaded.
+func (obj *Product) ListImages() ([]ProductImage, error) {
+	return obj.ListImagesCtx(context.Background())
+}
+
+func (obj *Product) ListImagesCtx(ctx context.Context) ([]ProductImage, error) {
+	endpoint := fmt.Sprintf("/admin/products/%d/images.json", obj.ID)
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]ProductImage{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["images"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = obj.api
+	}
+
+	return result, nil
+}
+
+func (obj *Product) Image(id int64) (*ProductImage, error) {
+	return obj.ImageCtx(context.Background(), id)
+}
+
+func (obj *Product) ImageCtx(ctx context.Context, id int64) (*ProductImage, error) {
+	endpoint := fmt.Sprintf("/admin/products/%d/images/%d.json", obj.ID, id)
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]ProductImage{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["image"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = obj.api
+
+	return &result, nil
+}
+
+// NewImage returns an unsaved image for the product. Set either Src to have
+// Shopify download the image, or attach the image with Attach.
+func (obj *Product) NewImage() *ProductImage {
+	return &ProductImage{ProductId: obj.ID, api: obj.api}
+}
+
+// Attach sets the image to upload from its raw bytes.
+func (obj *ProductImage) Attach(filename string, data []byte) {
+	obj.Filename = filename
+	obj.Attachment = base64.StdEncoding.EncodeToString(data)
+	obj.Src = ""
+}
+
+// Save creates the image or updates its position, alt text and variants.
+func (obj *ProductImage) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *ProductImage) SaveCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/products/%d/images/%d.json", obj.ProductId, obj.Id)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.Id == 0 {
+		endpoint = fmt.Sprintf("/admin/products/%d/images.json", obj.ProductId)
+		method = "POST"
+	}
+
+	body := map[string]*ProductImage{}
+	body["image"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]ProductImage{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["image"]
+	obj.api = api
+
+	return nil
+}
+
+func (obj *ProductImage) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *ProductImage) DeleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/products/%d/images/%d.json", obj.ProductId, obj.Id)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	return nil
+}
+
+// UploadImages uploads every image file in dir to the product, in file name
+// order. A file named after a variant's SKU, such as "TSHIRT-RED.jpg", is
+// associated with every variant of the product with that SKU, so
+// obj.Variants must be loaded. The uploaded images are appended to
+// obj.Images and returned.
+func (obj *Product) UploadImages(dir string) ([]ProductImage, error) {
+	return obj.UploadImagesCtx(context.Background(), dir)
+}
+
+func (obj *Product) UploadImagesCtx(ctx context.Context, dir string) ([]ProductImage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := map[string][]int64{}
+	for _, v := range obj.Variants {
+		if v.Sku != "" {
+			variants[v.Sku] = append(variants[v.Sku], v.Id)
+		}
+	}
+
+	result := []ProductImage{}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !imageExtensions[strings.ToLower(ext)] {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return result, err
+		}
+
+		image := obj.NewImage()
+		image.Attach(entry.Name(), data)
+		image.VariantIds = variants[strings.TrimSuffix(entry.Name(), ext)]
+
+		if err = image.SaveCtx(ctx); err != nil {
+			return result, fmt.Errorf("shopify: uploading %s: %w", entry.Name(), err)
+		}
+		result = append(result, *image)
+		obj.Images = append(obj.Images, *image)
+	}
+
+	return result, nil
+}
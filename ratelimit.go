@@ -0,0 +1,217 @@
+package shopify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a leaky bucket mirroring Shopify's per-shop REST call limit.
+// Every request adds one call to the bucket and calls leak out at REFILL_RATE,
+// scaled up for shops with a larger bucket: Shopify Plus shops have 80 calls
+// leaking at 4 per second.
+// Once the bucket passes the slowdown mark, requests are spaced out at the leak
+// rate so the shop never reaches the point where Shopify answers with a 429.
+//
+// GraphQL calls draw from a separate bucket of query cost points, restored
+// at the rate Shopify reports in each response's extensions.cost.
+//
+// A RateLimiter is safe for concurrent use. API values that don't set their
+// own Limiter share one per shop, so any number of goroutines and API values
+// talking to the same shop draw from a single bucket.
+type RateLimiter struct {
+	mu       sync.Mutex
+	level    float64       // calls currently in the bucket
+	limit    int           // bucket size reported by Shopify
+	interval time.Duration // time for one call to leak out
+	last     time.Time
+
+	// GraphQL query cost bucket, unknown until the first GraphQL response.
+	available   float64
+	maxCost     float64
+	restoreRate float64 // points per second
+	costLast    time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		limit:    BUCKET_LIMIT,
+		interval: time.Duration(REFILL_RATE * float64(time.Second)),
+	}
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = map[string]*RateLimiter{}
+)
+
+// limiterFor returns the limiter shared by every API talking to shop. The
+// limiter is kept until ForgetLimiter is called for the shop.
+func limiterFor(shop string) *RateLimiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	l, ok := limiters[shop]
+	if !ok {
+		l = NewRateLimiter()
+		limiters[shop] = l
+	}
+	return l
+}
+
+// ForgetLimiter drops the limiter shared by APIs talking to shop, which
+// otherwise lives as long as the process. Long-running processes that talk to
+// many shops should call it once they are done with a shop, e.g. when it
+// uninstalls the app.
+func ForgetLimiter(shop string) {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	delete(limiters, shop)
+}
+
+// Wait blocks until a call may be sent without overflowing the bucket, then
+// accounts for it. It returns ctx.Err() if ctx is done before then.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.drain(time.Now())
+
+	// Reserve the slot before sleeping so concurrent callers queue up behind
+	// each other instead of all waking at once.
+	var delay time.Duration
+	if over := l.level - l.slowdown() + 1; over > 0 {
+		delay = time.Duration(over * float64(l.interval))
+	}
+	l.level++
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		l.release()
+		return err
+	}
+	return nil
+}
+
+// Update records the X-Shopify-Shop-Api-Call-Limit values returned by Shopify,
+// which are authoritative over the local estimate. A larger bucket leaks
+// proportionally faster.
+func (l *RateLimiter) Update(calls, limit int) {
+	if limit <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.drain(time.Now())
+	l.level = float64(calls)
+	if limit != l.limit {
+		l.interval = l.interval * time.Duration(l.limit) / time.Duration(limit)
+		l.limit = limit
+	}
+}
+
+// Full marks the bucket as full, used when Shopify answers with a 429.
+func (l *RateLimiter) Full() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.drain(time.Now())
+	l.level = float64(l.limit)
+}
+
+// Status reports the current estimate of calls in the bucket and its size.
+func (l *RateLimiter) Status() (calls int, limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.drain(time.Now())
+	return int(l.level + 0.5), l.limit
+}
+
+func (l *RateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.level >= 1 {
+		l.level--
+	} else {
+		l.level = 0
+	}
+}
+
+// slowdown is the level past which calls are spaced out, scaled from
+// BUCKET_SLOWDOWN for shops with a larger bucket.
+func (l *RateLimiter) slowdown() float64 {
+	return float64(l.limit) * BUCKET_SLOWDOWN / BUCKET_LIMIT
+}
+
+func (l *RateLimiter) drain(now time.Time) {
+	if !l.last.IsZero() && l.interval > 0 {
+		l.level -= float64(now.Sub(l.last)) / float64(l.interval)
+		if l.level < 0 {
+			l.level = 0
+		}
+	}
+	l.last = now
+}
+
+// WaitCost blocks until cost GraphQL query points are available, then
+// accounts for them. Until Shopify has reported the bucket state it never
+// blocks.
+func (l *RateLimiter) WaitCost(ctx context.Context, cost float64) error {
+	l.mu.Lock()
+	l.restore(time.Now())
+
+	if l.maxCost == 0 || l.restoreRate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	if cost > l.maxCost {
+		cost = l.maxCost
+	}
+
+	var delay time.Duration
+	if missing := cost - l.available; missing > 0 {
+		delay = time.Duration(missing / l.restoreRate * float64(time.Second))
+	}
+	l.available -= cost
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		l.mu.Lock()
+		l.available += cost
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// UpdateCost records the throttle status reported by a GraphQL response.
+func (l *RateLimiter) UpdateCost(status GraphQLThrottleStatus) {
+	if status.MaximumAvailable <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.available = status.CurrentlyAvailable
+	l.maxCost = status.MaximumAvailable
+	l.restoreRate = status.RestoreRate
+	l.costLast = time.Now()
+}
+
+func (l *RateLimiter) restore(now time.Time) {
+	if !l.costLast.IsZero() && l.restoreRate > 0 {
+		l.available += now.Sub(l.costLast).Seconds() * l.restoreRate
+		if l.available > l.maxCost {
+			l.available = l.maxCost
+		}
+	}
+	l.costLast = now
+}
@@ -2,6 +2,7 @@ package shopify
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -12,22 +13,22 @@ const (
 )
 
 type RecurringApplicationCharge struct {
-	ActivatedOn        string `json:"activated_on,omitempty"`
-	APIClientID        int64  `json:"api_client_id,omitempty"`
-	BillingOn          string `json:"billing_on,omitempty"`
-	CancelledOn        string `json:"cancelled_on,omitempty"`
-	ConfirmationURL    string `json:"confirmation_url,omitempty"`
-	DecoratedReturnURL string `json:"decorated_return_url,omitempty"`
-	CreatedAt          string `json:"created_at,omitempty"`
-	ID                 int64  `json:"id,omitempty"`
-	Name               string `json:"name,omitempty"`
-	Status             string `json:"status,omitempty"`
-	Price              string `json:"price,omitempty"`
-	ReturnURL          string `json:"return_url,omitempty"`
-	Test               bool   `json:"test,omitempty"`
-	TrialDays          int    `json:"trial_days,omitempty"`
-	TrialEndsOn        string `json:"trial_ends_on,omitempty"`
-	UpdatedAt          string `json:"updated_at,omitempty"`
+	ActivatedOn        Timestamp `json:"activated_on,omitzero"`
+	APIClientID        int64     `json:"api_client_id,omitempty"`
+	BillingOn          Timestamp `json:"billing_on,omitzero"`
+	CancelledOn        Timestamp `json:"cancelled_on,omitzero"`
+	ConfirmationURL    string    `json:"confirmation_url,omitempty"`
+	DecoratedReturnURL string    `json:"decorated_return_url,omitempty"`
+	CreatedAt          Timestamp `json:"created_at,omitzero"`
+	ID                 int64     `json:"id,omitempty"`
+	Name               string    `json:"name,omitempty"`
+	Status             string    `json:"status,omitempty"`
+	Price              Money     `json:"price,omitzero"`
+	ReturnURL          string    `json:"return_url,omitempty"`
+	Test               bool      `json:"test,omitempty"`
+	TrialDays          int       `json:"trial_days,omitempty"`
+	TrialEndsOn        Timestamp `json:"trial_ends_on,omitzero"`
+	UpdatedAt          Timestamp `json:"updated_at,omitzero"`
 
 	api *API
 }
@@ -39,17 +40,21 @@ type RecurringApplicationChargeOptions struct {
 
 // RecurringApplicationCharges Retrieve all recurring application charges
 func (api *API) RecurringApplicationCharges(options *RecurringApplicationChargeOptions) ([]*RecurringApplicationCharge, error) {
+	return api.RecurringApplicationChargesCtx(context.Background(), options)
+}
+
+func (api *API) RecurringApplicationChargesCtx(ctx context.Context, options *RecurringApplicationChargeOptions) ([]*RecurringApplicationCharge, error) {
 
 	qs := encodeOptions(options)
 	endpoint := fmt.Sprintf("/admin/recurring_application_charges.json?%v", qs)
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]*RecurringApplicationCharge{}
@@ -66,17 +71,28 @@ func (api *API) RecurringApplicationCharges(options *RecurringApplicationChargeO
 	return result, nil
 }
 
+// RecurringApplicationChargesIter walks every page of recurring_application_charges.
+func (api *API) RecurringApplicationChargesIter(ctx context.Context, options *RecurringApplicationChargeOptions) *Iterator[RecurringApplicationCharge] {
+	return newIterator(ctx, api, "/admin/recurring_application_charges.json", "recurring_application_charges", options,
+		func(v *RecurringApplicationCharge) int64 { return v.ID },
+		func(v *RecurringApplicationCharge) { v.api = api })
+}
+
 func (api *API) RecurringApplicationCharge(id int64) (*RecurringApplicationCharge, error) {
+	return api.RecurringApplicationChargeCtx(context.Background(), id)
+}
+
+func (api *API) RecurringApplicationChargeCtx(ctx context.Context, id int64) (*RecurringApplicationCharge, error) {
 	endpoint := fmt.Sprintf("/admin/recurring_application_charges/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]RecurringApplicationCharge{}
@@ -98,6 +114,10 @@ func (api *API) NewRecurringApplicationCharge() *RecurringApplicationCharge {
 }
 
 func (obj *RecurringApplicationCharge) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *RecurringApplicationCharge) SaveCtx(ctx context.Context) error {
 
 	endpoint := fmt.Sprintf("/admin/recurring_application_charges.json")
 	method := "POST"
@@ -113,20 +133,14 @@ func (obj *RecurringApplicationCharge) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]RecurringApplicationCharge{}
@@ -144,48 +158,44 @@ func (obj *RecurringApplicationCharge) Save() error {
 }
 
 func (obj *RecurringApplicationCharge) Activate() error {
+	return obj.ActivateCtx(context.Background())
+}
+
+func (obj *RecurringApplicationCharge) ActivateCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/recurring_application_charges/%d/activate.json", obj.ID)
 	method := "POST"
 	expectedStatus := 200
 
-	res, status, err := obj.api.request(endpoint, method, nil, nil)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	return nil
 }
 
 func (obj *RecurringApplicationCharge) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *RecurringApplicationCharge) DeleteCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/recurring_application_charges/%d.json", obj.ID)
 	method := "DELETE"
 	expectedStatus := 200
 
-	res, status, err := obj.api.request(endpoint, method, nil, nil)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	return nil
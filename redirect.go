@@ -3,6 +3,8 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
@@ -19,14 +21,18 @@ type Redirect struct {
 }
 
 func (api *API) Redirects() ([]Redirect, error) {
-	res, status, err := api.request("/admin/redirects.json", "GET", nil, nil)
+	return api.RedirectsCtx(context.Background())
+}
+
+func (api *API) RedirectsCtx(ctx context.Context) ([]Redirect, error) {
+	res, status, err := api.requestContext(ctx, "/admin/redirects.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Redirect{}
@@ -37,24 +43,35 @@ func (api *API) Redirects() ([]Redirect, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
+// RedirectsIter walks every page of redirects.
+func (api *API) RedirectsIter(ctx context.Context, options *ListOptions) *Iterator[Redirect] {
+	return newIterator(ctx, api, "/admin/redirects.json", "redirects", options,
+		func(v *Redirect) int64 { return v.Id },
+		func(v *Redirect) { v.api = api })
+}
+
 func (api *API) Redirect(id int64) (*Redirect, error) {
+	return api.RedirectCtx(context.Background(), id)
+}
+
+func (api *API) RedirectCtx(ctx context.Context, id int64) (*Redirect, error) {
 	endpoint := fmt.Sprintf("/admin/redirects/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Redirect{}
@@ -76,9 +93,13 @@ func (api *API) NewRedirect() *Redirect {
 }
 
 func (obj *Redirect) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Redirect) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/redirects/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/redirects.json")
@@ -96,20 +117,14 @@ func (obj *Redirect) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Redirect{}
@@ -118,7 +133,9 @@ func (obj *Redirect) Save() error {
 	if err != nil {
 		return err
 	}
+	api := obj.api
 	*obj = r["redirect"]
+	obj.api = api
 
 	return nil
 }
@@ -0,0 +1,217 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+)
+
+// Restock types of a RefundLineItem.
+const (
+	RestockNone   = "no_restock"
+	RestockCancel = "cancel" // the item was never fulfilled
+	RestockReturn = "return" // the item was fulfilled and sent back
+)
+
+type Refund struct {
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	Currency string `json:"currency,omitempty"`
+
+	Id int64 `json:"id,omitempty"`
+
+	Note string `json:"note,omitempty"`
+
+	Notify bool `json:"notify,omitempty"`
+
+	OrderId int64 `json:"order_id,omitempty"`
+
+	ProcessedAt Timestamp `json:"processed_at,omitzero"`
+
+	RefundLineItems []RefundLineItem `json:"refund_line_items,omitempty"`
+
+	Shipping *RefundShipping `json:"shipping,omitempty"`
+
+	Transactions []Transaction `json:"transactions,omitempty"`
+
+	UserId int64 `json:"user_id,omitempty"`
+}
+
+type RefundLineItem struct {
+	Id int64 `json:"id,omitempty"`
+
+	LineItem *LineItem `json:"line_item,omitempty"`
+
+	LineItemId int64 `json:"line_item_id"`
+
+	LocationId int64 `json:"location_id,omitempty"`
+
+	Quantity int64 `json:"quantity"`
+
+	RestockType string `json:"restock_type,omitempty"`
+
+	Subtotal Money `json:"subtotal,omitzero"`
+
+	TotalTax Money `json:"total_tax,omitzero"`
+}
+
+// RefundShipping asks for either the full shipping cost or Amount of it to be
+// refunded.
+type RefundShipping struct {
+	Amount Money `json:"amount,omitzero"`
+
+	FullRefund bool `json:"full_refund,omitempty"`
+
+	MaximumRefundable Money `json:"maximum_refundable,omitzero"`
+}
+
+// AddLineItem refunds quantity of the order's line item. Returned or cancelled
+// items are restocked at location, which may be nil for RestockNone.
+func (obj *Refund) AddLineItem(lineItemId, quantity int64, restockType string, location *Location) {
+	item := RefundLineItem{LineItemId: lineItemId, Quantity: quantity, RestockType: restockType}
+	if location != nil {
+		item.LocationId = location.Id
+	}
+	obj.RefundLineItems = append(obj.RefundLineItems, item)
+}
+
+// bind attaches api to the refund's transactions, so they can be captured or
+// voided.
+func (obj *Refund) bind(api *API) {
+	for i := range obj.Transactions {
+		obj.Transactions[i].api = api
+	}
+}
+
+// ListRefunds fetches the order's refunds. The Refunds field holds the ones
+// embedded in the order when it was loaded.
+func (obj *Order) ListRefunds() ([]Refund, error) {
+	return obj.ListRefundsCtx(context.Background())
+}
+
+func (obj *Order) ListRefundsCtx(ctx context.Context) ([]Refund, error) {
+	endpoint := fmt.Sprintf("/admin/orders/%d/refunds.json", obj.Id)
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]Refund{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["refunds"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].bind(obj.api)
+	}
+
+	return result, nil
+}
+
+func (obj *Order) Refund(id int64) (*Refund, error) {
+	return obj.RefundCtx(context.Background(), id)
+}
+
+func (obj *Order) RefundCtx(ctx context.Context, id int64) (*Refund, error) {
+	endpoint := fmt.Sprintf("/admin/orders/%d/refunds/%d.json", obj.Id, id)
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]Refund{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["refund"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.bind(obj.api)
+
+	return &result, nil
+}
+
+// CalculateRefund asks Shopify what refund would result from the given line
+// items and shipping. The returned refund carries suggested transactions,
+// which can be turned into refund transactions by setting their Kind to
+// TransactionRefund before passing it to CreateRefund.
+func (obj *Order) CalculateRefund(refund *Refund) (*Refund, error) {
+	return obj.CalculateRefundCtx(context.Background(), refund)
+}
+
+func (obj *Order) CalculateRefundCtx(ctx context.Context, refund *Refund) (*Refund, error) {
+	endpoint := fmt.Sprintf("/admin/orders/%d/refunds/calculate.json", obj.Id)
+
+	result := &Refund{}
+	err := obj.refund(ctx, endpoint, 200, refund, result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateRefund issues refund and updates it with the refund Shopify created.
+func (obj *Order) CreateRefund(refund *Refund) error {
+	return obj.CreateRefundCtx(context.Background(), refund)
+}
+
+func (obj *Order) CreateRefundCtx(ctx context.Context, refund *Refund) error {
+	endpoint := fmt.Sprintf("/admin/orders/%d/refunds.json", obj.Id)
+	return obj.refund(ctx, endpoint, 201, refund, refund)
+}
+
+func (obj *Order) refund(ctx context.Context, endpoint string, expectedStatus int, refund *Refund, result *Refund) error {
+	body := map[string]*Refund{}
+	body["refund"] = refund
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]Refund{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	*result = r["refund"]
+	result.bind(obj.api)
+
+	return nil
+}
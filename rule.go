@@ -1,5 +1,63 @@
 package shopify
 
+import (
+	"fmt"
+
+	"strconv"
+
+	"strings"
+)
+
+// Values of Rule.Column.
+const (
+	RuleColumnTag                   = "tag"
+	RuleColumnTitle                 = "title"
+	RuleColumnType                  = "type"
+	RuleColumnVendor                = "vendor"
+	RuleColumnVariantTitle          = "variant_title"
+	RuleColumnVariantPrice          = "variant_price"
+	RuleColumnVariantCompareAtPrice = "variant_compare_at_price"
+	RuleColumnVariantWeight         = "variant_weight"
+	RuleColumnVariantInventory      = "variant_inventory"
+	RuleColumnIsPriceReduced        = "is_price_reduced"
+)
+
+// Values of Rule.Relation.
+const (
+	RuleRelationEquals      = "equals"
+	RuleRelationNotEquals   = "not_equals"
+	RuleRelationGreaterThan = "greater_than"
+	RuleRelationLessThan    = "less_than"
+	RuleRelationStartsWith  = "starts_with"
+	RuleRelationEndsWith    = "ends_with"
+	RuleRelationContains    = "contains"
+	RuleRelationNotContains = "not_contains"
+	RuleRelationIsSet       = "is_set"
+	RuleRelationIsNotSet    = "is_not_set"
+)
+
+var (
+	textRelations    = []string{RuleRelationEquals, RuleRelationNotEquals, RuleRelationStartsWith, RuleRelationEndsWith, RuleRelationContains, RuleRelationNotContains}
+	numberRelations  = []string{RuleRelationEquals, RuleRelationNotEquals, RuleRelationGreaterThan, RuleRelationLessThan}
+	presentRelations = []string{RuleRelationIsSet, RuleRelationIsNotSet}
+)
+
+// ruleRelations lists the relations Shopify accepts for each column.
+var ruleRelations = map[string][]string{
+	RuleColumnTag:                   {RuleRelationEquals},
+	RuleColumnTitle:                 textRelations,
+	RuleColumnType:                  textRelations,
+	RuleColumnVendor:                textRelations,
+	RuleColumnVariantTitle:          textRelations,
+	RuleColumnVariantPrice:          numberRelations,
+	RuleColumnVariantCompareAtPrice: numberRelations,
+	RuleColumnVariantWeight:         numberRelations,
+	RuleColumnVariantInventory:      numberRelations,
+	RuleColumnIsPriceReduced:        presentRelations,
+}
+
+// Rule is one condition of a SmartCollection, e.g. {"vendor", "equals",
+// "Acme"}.
 type Rule struct {
 	Column string `json:"column"`
 
@@ -7,3 +65,169 @@ type Rule struct {
 
 	Condition string `json:"condition"`
 }
+
+// Validate checks the rule uses a known column, a relation that column
+// supports, and a condition of the right kind.
+func (r Rule) Validate() error {
+	relations, ok := ruleRelations[r.Column]
+	if !ok {
+		return fmt.Errorf("shopify: unknown rule column %q", r.Column)
+	}
+
+	known := false
+	for _, relation := range relations {
+		known = known || relation == r.Relation
+	}
+	if !known {
+		return fmt.Errorf("shopify: rule column %q doesn't support relation %q", r.Column, r.Relation)
+	}
+
+	switch {
+	case r.Column == RuleColumnIsPriceReduced:
+	case r.isMoney():
+		if _, err := ParseMoney(r.Condition, ""); err != nil {
+			return fmt.Errorf("shopify: rule on %q needs an amount, got %q", r.Column, r.Condition)
+		}
+	case r.isNumber():
+		if _, err := strconv.ParseFloat(r.Condition, 64); err != nil {
+			return fmt.Errorf("shopify: rule on %q needs a number, got %q", r.Column, r.Condition)
+		}
+	case strings.TrimSpace(r.Condition) == "":
+		return fmt.Errorf("shopify: rule on %q has no condition", r.Column)
+	}
+
+	return nil
+}
+
+func (r Rule) isMoney() bool {
+	return r.Column == RuleColumnVariantPrice || r.Column == RuleColumnVariantCompareAtPrice
+}
+
+func (r Rule) isNumber() bool {
+	return r.Column == RuleColumnVariantWeight || r.Column == RuleColumnVariantInventory
+}
+
+// Matches reports whether the product meets the rule, the way Shopify
+// decides it: text is compared case-insensitively and a tag rule matches any
+// one of the product's tags. A variant rule matches when any variant meets
+// it, except not_equals and not_contains, which need every variant to. An
+// invalid rule matches nothing.
+func (r Rule) Matches(p *Product) bool {
+	if r.Validate() != nil {
+		return false
+	}
+
+	switch r.Column {
+	case RuleColumnTag:
+		for _, tag := range strings.Split(p.Tags, ",") {
+			if strings.EqualFold(strings.TrimSpace(tag), strings.TrimSpace(r.Condition)) {
+				return true
+			}
+		}
+		return false
+	case RuleColumnTitle:
+		return r.matchText(p.Title)
+	case RuleColumnType:
+		return r.matchText(p.ProductType)
+	case RuleColumnVendor:
+		return r.matchText(p.Vendor)
+	case RuleColumnIsPriceReduced:
+		reduced := false
+		for _, v := range p.Variants {
+			reduced = reduced || (!v.CompareAtPrice.IsZero() && v.CompareAtPrice.Cmp(v.Price) > 0)
+		}
+		return reduced == (r.Relation == RuleRelationIsSet)
+	}
+
+	negated := r.Relation == RuleRelationNotEquals || r.Relation == RuleRelationNotContains
+	for _, v := range p.Variants {
+		if r.matchVariant(&v) != negated {
+			return !negated
+		}
+	}
+	return negated && len(p.Variants) > 0
+}
+
+func (r Rule) matchVariant(v *Variant) bool {
+	switch r.Column {
+	case RuleColumnVariantTitle:
+		return r.matchText(v.Title)
+	case RuleColumnVariantPrice:
+		return r.matchMoney(v.Price)
+	case RuleColumnVariantCompareAtPrice:
+		return r.matchMoney(v.CompareAtPrice)
+	case RuleColumnVariantWeight:
+		return r.matchNumber(v.Weight)
+	case RuleColumnVariantInventory:
+		if v.InventoryQuantity == nil {
+			return r.matchNumber(0)
+		}
+		return r.matchNumber(float64(*v.InventoryQuantity))
+	}
+	return false
+}
+
+func (r Rule) matchText(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	condition := strings.ToLower(strings.TrimSpace(r.Condition))
+
+	switch r.Relation {
+	case RuleRelationEquals:
+		return s == condition
+	case RuleRelationNotEquals:
+		return s != condition
+	case RuleRelationStartsWith:
+		return strings.HasPrefix(s, condition)
+	case RuleRelationEndsWith:
+		return strings.HasSuffix(s, condition)
+	case RuleRelationContains:
+		return strings.Contains(s, condition)
+	case RuleRelationNotContains:
+		return !strings.Contains(s, condition)
+	}
+	return false
+}
+
+func (r Rule) matchNumber(n float64) bool {
+	condition, err := strconv.ParseFloat(r.Condition, 64)
+	if err != nil {
+		return false
+	}
+
+	switch r.Relation {
+	case RuleRelationEquals:
+		return n == condition
+	case RuleRelationNotEquals:
+		return n != condition
+	case RuleRelationGreaterThan:
+		return n > condition
+	case RuleRelationLessThan:
+		return n < condition
+	}
+	return false
+}
+
+// matchMoney compares amounts exactly. An unset amount, such as a missing
+// compare at price, only matches not_equals.
+func (r Rule) matchMoney(m Money) bool {
+	condition, err := ParseMoney(r.Condition, m.Currency)
+	if err != nil {
+		return false
+	}
+
+	if m.IsZero() {
+		return r.Relation == RuleRelationNotEquals
+	}
+
+	switch r.Relation {
+	case RuleRelationEquals:
+		return m.Cmp(condition) == 0
+	case RuleRelationNotEquals:
+		return m.Cmp(condition) != 0
+	case RuleRelationGreaterThan:
+		return m.Cmp(condition) > 0
+	case RuleRelationLessThan:
+		return m.Cmp(condition) < 0
+	}
+	return false
+}
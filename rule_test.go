@@ -0,0 +1,96 @@
+package shopify
+
+import (
+	"testing"
+)
+
+func TestRuleValidate(t *testing.T) {
+	valid := []Rule{
+		{RuleColumnTag, RuleRelationEquals, "sale"},
+		{RuleColumnVendor, RuleRelationStartsWith, "Ac"},
+		{RuleColumnVariantPrice, RuleRelationLessThan, "19.99"},
+		{RuleColumnVariantInventory, RuleRelationGreaterThan, "0"},
+		{RuleColumnIsPriceReduced, RuleRelationIsSet, ""},
+	}
+	for _, r := range valid {
+		if err := r.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", r, err)
+		}
+	}
+
+	invalid := []Rule{
+		{"colour", RuleRelationEquals, "red"},
+		{RuleColumnTag, RuleRelationContains, "sale"},
+		{RuleColumnTitle, RuleRelationGreaterThan, "a"},
+		{RuleColumnVariantPrice, RuleRelationEquals, "cheap"},
+		{RuleColumnVariantWeight, RuleRelationLessThan, ""},
+		{RuleColumnVendor, RuleRelationEquals, " "},
+	}
+	for _, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", r)
+		}
+	}
+}
+
+func TestSmartCollectionMatches(t *testing.T) {
+	none, four := int64(0), int64(4)
+	p := &Product{
+		Title:       "Blue Shirt",
+		ProductType: "Shirts",
+		Vendor:      "Acme",
+		Tags:        "Summer, sale",
+		Variants: []Variant{
+			{Title: "Small", Price: MustParseMoney("20.00", ""), CompareAtPrice: MustParseMoney("25.00", ""), InventoryQuantity: &none, Weight: 0.2},
+			{Title: "Large", Price: MustParseMoney("22.50", ""), InventoryQuantity: &four, Weight: 0.3},
+		},
+	}
+
+	tests := []struct {
+		rule  Rule
+		match bool
+	}{
+		{Rule{RuleColumnTag, RuleRelationEquals, "SALE"}, true},
+		{Rule{RuleColumnTag, RuleRelationEquals, "sal"}, false},
+		{Rule{RuleColumnTitle, RuleRelationContains, "shirt"}, true},
+		{Rule{RuleColumnTitle, RuleRelationNotContains, "shirt"}, false},
+		{Rule{RuleColumnType, RuleRelationEquals, "shirts"}, true},
+		{Rule{RuleColumnVendor, RuleRelationEndsWith, "me"}, true},
+		{Rule{RuleColumnVariantTitle, RuleRelationEquals, "large"}, true},
+		{Rule{RuleColumnVariantTitle, RuleRelationNotEquals, "large"}, false},
+		{Rule{RuleColumnVariantTitle, RuleRelationNotContains, "medium"}, true},
+		{Rule{RuleColumnVariantPrice, RuleRelationGreaterThan, "22"}, true},
+		{Rule{RuleColumnVariantPrice, RuleRelationLessThan, "20"}, false},
+		{Rule{RuleColumnVariantPrice, RuleRelationEquals, "22.5"}, true},
+		{Rule{RuleColumnVariantCompareAtPrice, RuleRelationGreaterThan, "24"}, true},
+		{Rule{RuleColumnVariantInventory, RuleRelationGreaterThan, "3"}, true},
+		{Rule{RuleColumnVariantWeight, RuleRelationLessThan, "0.1"}, false},
+		{Rule{RuleColumnIsPriceReduced, RuleRelationIsSet, ""}, true},
+		{Rule{RuleColumnIsPriceReduced, RuleRelationIsNotSet, ""}, false},
+	}
+	for _, test := range tests {
+		if got := test.rule.Matches(p); got != test.match {
+			t.Errorf("%+v: expected %v, got %v", test.rule, test.match, got)
+		}
+	}
+
+	c := &SmartCollection{Rules: []Rule{
+		{RuleColumnVendor, RuleRelationEquals, "Acme"},
+		{RuleColumnVariantPrice, RuleRelationLessThan, "10"},
+	}}
+	if c.Matches(p) {
+		t.Errorf("Expected product not to meet every rule")
+	}
+	c.Disjunctive = true
+	if !c.Matches(p) {
+		t.Errorf("Expected product to meet one rule")
+	}
+
+	c.Rules = append(c.Rules, Rule{"colour", RuleRelationEquals, "blue"})
+	if c.Validate() == nil || c.Matches(p) {
+		t.Errorf("Expected an invalid rule set to match nothing")
+	}
+	if (&SmartCollection{}).Validate() == nil {
+		t.Errorf("Expected a collection without rules to be invalid")
+	}
+}
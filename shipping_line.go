@@ -1,17 +1,13 @@
 package shopify
 
-import (
-	"time"
-)
-
 type ShippingLine struct {
-	Code string `json:"code"`
+	Code string `json:"code,omitempty"`
 
-	Price time.Time `json:"price"`
+	Price Money `json:"price,omitzero"`
 
-	Source string `json:"source"`
+	Source string `json:"source,omitempty"`
 
-	Title string `json:"title"`
+	Title string `json:"title,omitempty"`
 
-	TaxLines []interface{} `json:"tax_lines"`
+	TaxLines []interface{} `json:"tax_lines,omitempty"`
 }
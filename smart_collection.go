@@ -3,11 +3,11 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
-
-	"time"
 )
 
 type SmartCollection struct {
@@ -19,7 +19,7 @@ type SmartCollection struct {
 
 	Id int64 `json:"id"`
 
-	PublishedAt time.Time `json:"published_at"`
+	PublishedAt Timestamp `json:"published_at,omitzero"`
 
 	PublishedScope string `json:"published_scope"`
 
@@ -29,7 +29,7 @@ type SmartCollection struct {
 
 	Title string `json:"title"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
 	Rules []Rule `json:"rules"`
 
@@ -37,14 +37,18 @@ type SmartCollection struct {
 }
 
 func (api *API) SmartCollections() ([]SmartCollection, error) {
-	res, status, err := api.request("/admin/smart_collections.json", "GET", nil, nil)
+	return api.SmartCollectionsCtx(context.Background())
+}
+
+func (api *API) SmartCollectionsCtx(ctx context.Context) ([]SmartCollection, error) {
+	res, status, err := api.requestContext(ctx, "/admin/smart_collections.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]SmartCollection{}
@@ -56,24 +60,35 @@ func (api *API) SmartCollections() ([]SmartCollection, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
+// SmartCollectionsIter walks every page of smart_collections.
+func (api *API) SmartCollectionsIter(ctx context.Context, options *ListOptions) *Iterator[SmartCollection] {
+	return newIterator(ctx, api, "/admin/smart_collections.json", "smart_collections", options,
+		func(v *SmartCollection) int64 { return v.Id },
+		func(v *SmartCollection) { v.api = api })
+}
+
 func (api *API) SmartCollection(id int64) (*SmartCollection, error) {
+	return api.SmartCollectionCtx(context.Background(), id)
+}
+
+func (api *API) SmartCollectionCtx(ctx context.Context, id int64) (*SmartCollection, error) {
 	endpoint := fmt.Sprintf("/admin/smart_collections/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]SmartCollection{}
@@ -94,9 +109,13 @@ func (api *API) NewSmartCollection() *SmartCollection {
 }
 
 func (obj *SmartCollection) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *SmartCollection) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/smart_collections/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/smart_collections.json")
@@ -114,20 +133,14 @@ func (obj *SmartCollection) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]SmartCollection{}
@@ -137,7 +150,42 @@ func (obj *SmartCollection) Save() error {
 		return err
 	}
 
+	api := obj.api
 	*obj = r["smart_collection"]
+	obj.api = api
+
+	return nil
+}
+
+// Validate checks the collection has rules and that each of them is valid.
+func (obj *SmartCollection) Validate() error {
+	if len(obj.Rules) == 0 {
+		return fmt.Errorf("shopify: smart collection %q has no rules", obj.Title)
+	}
+
+	for i, rule := range obj.Rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
 
 	return nil
 }
+
+// Matches reports whether Shopify would put the product in the collection:
+// it must meet every rule, or any one of them if the collection is
+// Disjunctive. This previews a rule set locally; the product needs its
+// variants and tags loaded.
+func (obj *SmartCollection) Matches(p *Product) bool {
+	if obj.Validate() != nil {
+		return false
+	}
+
+	for _, rule := range obj.Rules {
+		if rule.Matches(p) == obj.Disjunctive {
+			return obj.Disjunctive
+		}
+	}
+
+	return !obj.Disjunctive
+}
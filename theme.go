@@ -3,15 +3,15 @@ package shopify
 import (
 	"bytes"
 
+	"context"
+
 	"encoding/json"
 
 	"fmt"
-
-	"time"
 )
 
 type Theme struct {
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Timestamp `json:"created_at,omitzero"`
 
 	Id int64 `json:"id"`
 
@@ -21,7 +21,7 @@ type Theme struct {
 
 	ThemeStoreId string `json:"theme_store_id"`
 
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Timestamp `json:"updated_at,omitzero"`
 
 	Previewable bool `json:"previewable"`
 
@@ -31,14 +31,18 @@ type Theme struct {
 }
 
 func (api *API) Themes() ([]Theme, error) {
-	res, status, err := api.request("/admin/themes.json", "GET", nil, nil)
+	return api.ThemesCtx(context.Background())
+}
+
+func (api *API) ThemesCtx(ctx context.Context) ([]Theme, error) {
+	res, status, err := api.requestContext(ctx, "/admin/themes.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]Theme{}
@@ -50,24 +54,28 @@ func (api *API) Themes() ([]Theme, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
 func (api *API) Theme(id int64) (*Theme, error) {
+	return api.ThemeCtx(context.Background(), id)
+}
+
+func (api *API) ThemeCtx(ctx context.Context, id int64) (*Theme, error) {
 	endpoint := fmt.Sprintf("/admin/themes/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Theme{}
@@ -89,9 +97,13 @@ func (api *API) NewTheme() *Theme {
 }
 
 func (obj *Theme) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Theme) SaveCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/themes/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
 		endpoint = fmt.Sprintf("/admin/themes.json")
@@ -109,20 +121,14 @@ func (obj *Theme) Save() error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Theme{}
@@ -132,7 +138,9 @@ func (obj *Theme) Save() error {
 		return err
 	}
 
+	api := obj.api
 	*obj = r["theme"]
+	obj.api = api
 
 	return nil
 }
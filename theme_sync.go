@@ -0,0 +1,392 @@
+package shopify
+
+import (
+	"context"
+
+	"crypto/md5"
+
+	"encoding/base64"
+
+	"encoding/hex"
+
+	"fmt"
+
+	"io/fs"
+
+	"maps"
+
+	"os"
+
+	"path"
+
+	"path/filepath"
+
+	"slices"
+
+	"strings"
+
+	"time"
+
+	"unicode/utf8"
+)
+
+// THEME_DIRS are the top-level directories of a theme. ThemeSync leaves
+// anything else in its directory alone.
+var THEME_DIRS = []string{"assets", "blocks", "config", "layout", "locales", "sections", "snippets", "templates"}
+
+// THEME_SYNC_IGNORE is the default ThemeSync.Ignore. settings_data.json
+// holds the merchant's theme customisations, which a push would overwrite.
+var THEME_SYNC_IGNORE = []string{"config/settings_data.json"}
+
+// ThemeSync mirrors a theme's assets to a local directory, where each asset
+// key is a path relative to Dir, e.g. templates/index.liquid.
+type ThemeSync struct {
+	ThemeId int64
+
+	Dir string
+
+	// Ignore holds path.Match patterns of asset keys, such as "assets/*.map",
+	// that are never pulled, pushed or deleted.
+	Ignore []string
+
+	// Debounce is how long Watch waits for changes to settle, or
+	// THEME_WATCH_DEBOUNCE if zero.
+	Debounce time.Duration
+
+	// AllowDeleteAll lets Push delete the theme's assets when Dir holds no
+	// theme files at all, or none of THEME_DIRS, which is otherwise taken to
+	// be a mistake. Dir itself must still exist.
+	AllowDeleteAll bool
+
+	api *API
+}
+
+// ThemeDiff lists the asset keys a push changes.
+type ThemeDiff struct {
+	Created []string
+
+	Updated []string
+
+	Deleted []string
+}
+
+// Empty reports whether the push has nothing to do.
+func (d *ThemeDiff) Empty() bool {
+	return len(d.Created) == 0 && len(d.Updated) == 0 && len(d.Deleted) == 0
+}
+
+// String lists the changes one per line, prefixed with +, ~ or -.
+func (d *ThemeDiff) String() string {
+	b := &strings.Builder{}
+	for _, key := range d.Created {
+		fmt.Fprintf(b, "+ %s\n", key)
+	}
+	for _, key := range d.Updated {
+		fmt.Fprintf(b, "~ %s\n", key)
+	}
+	for _, key := range d.Deleted {
+		fmt.Fprintf(b, "- %s\n", key)
+	}
+	return b.String()
+}
+
+func (api *API) NewThemeSync(themeId int64, dir string) *ThemeSync {
+	return &ThemeSync{ThemeId: themeId, Dir: dir, Ignore: slices.Clone(THEME_SYNC_IGNORE), api: api}
+}
+
+// Ignored reports whether key matches one of the Ignore patterns.
+func (obj *ThemeSync) Ignored(key string) bool {
+	for _, pattern := range obj.Ignore {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Pull downloads every asset that is missing locally or differs from the
+// local copy, and returns their keys.
+func (obj *ThemeSync) Pull(ctx context.Context) ([]string, error) {
+	remote, err := obj.remote(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := obj.local()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, key := range slices.Sorted(maps.Keys(remote)) {
+		if sum, ok := local[key]; ok && sum == remote[key] {
+			continue
+		}
+
+		data, err := obj.download(ctx, key)
+		if err != nil {
+			return result, fmt.Errorf("shopify: pulling %s: %w", key, err)
+		}
+		if sum, ok := local[key]; ok && sum == checksum(data) {
+			continue
+		}
+
+		file := obj.path(key)
+		if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return result, err
+		}
+		if err = os.WriteFile(file, data, 0644); err != nil {
+			return result, err
+		}
+
+		result = append(result, key)
+	}
+
+	return result, nil
+}
+
+// Diff compares the local directory with the theme, by checksum, and returns
+// what Push would do. Assets Shopify sends without a checksum are downloaded
+// and compared by content. It fails if Dir doesn't exist or has none of
+// THEME_DIRS.
+func (obj *ThemeSync) Diff(ctx context.Context) (*ThemeDiff, error) {
+	if err := obj.checkDir(); err != nil {
+		return nil, err
+	}
+	return obj.diff(ctx)
+}
+
+func (obj *ThemeSync) diff(ctx context.Context) (*ThemeDiff, error) {
+	remote, err := obj.remote(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := obj.local()
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &ThemeDiff{Created: []string{}, Updated: []string{}, Deleted: []string{}}
+	for _, key := range slices.Sorted(maps.Keys(local)) {
+		sum, ok := remote[key]
+		if ok && sum == "" {
+			data, err := obj.download(ctx, key)
+			if err != nil {
+				return nil, fmt.Errorf("shopify: comparing %s: %w", key, err)
+			}
+			sum = checksum(data)
+		}
+		switch {
+		case !ok:
+			diff.Created = append(diff.Created, key)
+		case sum != local[key]:
+			diff.Updated = append(diff.Updated, key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(remote)) {
+		if _, ok := local[key]; !ok {
+			diff.Deleted = append(diff.Deleted, key)
+		}
+	}
+
+	return diff, nil
+}
+
+// Push uploads the created and updated files of diff and deletes the theme's
+// assets that were removed locally. A nil diff pushes the current Diff, so
+// callers can show the diff first and then push exactly that. Unless
+// AllowDeleteAll is set, Push refuses to delete anything while Dir holds no
+// theme files, and a nil diff needs Dir to have one of THEME_DIRS.
+func (obj *ThemeSync) Push(ctx context.Context, diff *ThemeDiff) (*ThemeDiff, error) {
+	if diff == nil {
+		var err error
+		if obj.AllowDeleteAll {
+			if _, err = os.Stat(obj.Dir); err != nil {
+				return nil, fmt.Errorf("shopify: theme directory: %w", err)
+			}
+			diff, err = obj.diff(ctx)
+		} else {
+			diff, err = obj.Diff(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(diff.Deleted) > 0 && !obj.AllowDeleteAll {
+		if err := obj.checkDir(); err != nil {
+			return diff, err
+		}
+		local, err := obj.local()
+		if err != nil {
+			return diff, err
+		}
+		if len(local) == 0 {
+			return diff, fmt.Errorf("shopify: %s has no theme files, refusing to delete %d assets", obj.Dir, len(diff.Deleted))
+		}
+	}
+
+	for _, key := range append(slices.Clone(diff.Created), diff.Updated...) {
+		if err := obj.upload(ctx, key); err != nil {
+			return diff, err
+		}
+	}
+
+	for _, key := range diff.Deleted {
+		if obj.Ignored(key) {
+			continue
+		}
+		if err := obj.api.DeleteCtx(ctx, obj.ThemeId, key); err != nil {
+			return diff, fmt.Errorf("shopify: deleting %s: %w", key, err)
+		}
+	}
+
+	return diff, nil
+}
+
+// download fetches the contents of the asset for key.
+func (obj *ThemeSync) download(ctx context.Context, key string) ([]byte, error) {
+	asset, err := obj.api.AssetCtx(ctx, obj.ThemeId, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Attachment != "" {
+		return base64.StdEncoding.DecodeString(asset.Attachment)
+	}
+	return []byte(asset.Value), nil
+}
+
+// upload sends the local file for key, as text if it is UTF-8 and as a
+// base64 attachment otherwise.
+func (obj *ThemeSync) upload(ctx context.Context, key string) error {
+	if obj.Ignored(key) {
+		return nil
+	}
+
+	data, err := os.ReadFile(obj.path(key))
+	if err != nil {
+		return err
+	}
+
+	upload := &AssetUpload{Key: key, api: obj.api}
+	if utf8.Valid(data) && !slices.Contains(data, 0) {
+		upload.Value = string(data)
+	} else {
+		upload.Attachment = base64.StdEncoding.EncodeToString(data)
+	}
+
+	if err = upload.UploadCtx(ctx, obj.ThemeId); err != nil {
+		return fmt.Errorf("shopify: pushing %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// remote returns the checksum of each of the theme's assets, leaving out
+// keys that don't map to a file under Dir.
+func (obj *ThemeSync) remote(ctx context.Context) (map[string]string, error) {
+	assets, err := obj.api.AssetsCtx(ctx, obj.ThemeId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]string{}
+	for _, asset := range assets {
+		if themeKey(asset.Key) && !obj.Ignored(asset.Key) {
+			result[asset.Key] = asset.Checksum
+		}
+	}
+	return result, nil
+}
+
+// checkDir makes sure Dir exists and holds at least one of THEME_DIRS, so a
+// mistyped or unmounted Dir isn't taken for a theme with no files.
+func (obj *ThemeSync) checkDir() error {
+	info, err := os.Stat(obj.Dir)
+	if err != nil {
+		return fmt.Errorf("shopify: theme directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("shopify: theme directory %s is not a directory", obj.Dir)
+	}
+
+	for _, dir := range THEME_DIRS {
+		if info, err := os.Stat(filepath.Join(obj.Dir, dir)); err == nil && info.IsDir() {
+			return nil
+		}
+	}
+	return fmt.Errorf("shopify: %s has none of the theme directories %s", obj.Dir, strings.Join(THEME_DIRS, ", "))
+}
+
+// local returns the checksum of each file under the theme directories of
+// Dir, skipping hidden files.
+func (obj *ThemeSync) local() (map[string]string, error) {
+	result := map[string]string{}
+
+	for _, dir := range THEME_DIRS {
+		root := filepath.Join(obj.Dir, dir)
+		err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+			if os.IsNotExist(err) && file == root {
+				return filepath.SkipDir
+			}
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			key, err := obj.key(file)
+			if err != nil || obj.Ignored(key) {
+				return err
+			}
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			result[key] = checksum(data)
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// key returns the asset key of a file under Dir.
+func (obj *ThemeSync) key(file string) (string, error) {
+	rel, err := filepath.Rel(obj.Dir, file)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func (obj *ThemeSync) path(key string) string {
+	return filepath.Join(obj.Dir, filepath.FromSlash(key))
+}
+
+// checksum returns the MD5 of data in hex, as Shopify sends for assets.
+func checksum(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// themeKey reports whether key is a plain path inside one of THEME_DIRS,
+// so it can't be written outside Dir.
+func themeKey(key string) bool {
+	dir, _, ok := strings.Cut(key, "/")
+	return ok && path.Clean(key) == key && slices.Contains(THEME_DIRS, dir)
+}
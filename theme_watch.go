@@ -0,0 +1,243 @@
+package shopify
+
+import (
+	"context"
+
+	"errors"
+
+	"fmt"
+
+	"io/fs"
+
+	"maps"
+
+	"os"
+
+	"path/filepath"
+
+	"slices"
+
+	"strings"
+
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// THEME_WATCH_DEBOUNCE is how long ThemeSync.Watch waits for files to stop
+// changing before it uploads them, since editors often write a file several
+// times per save.
+const THEME_WATCH_DEBOUNCE = 300 * time.Millisecond
+
+// ThemeWatchEvent reports what Watch did with a changed file.
+type ThemeWatchEvent struct {
+	Key string
+
+	// Deleted is set when the file was removed locally and the asset deleted.
+	Deleted bool
+
+	Err error
+}
+
+// Watch uploads files under Dir as they change, and deletes the assets of
+// files that are removed, until ctx is cancelled. Changes are batched until
+// Debounce passes without another one, then sent one at a time through the
+// shop's rate limiter. Each upload or delete is reported to callback; a
+// failure doesn't stop the watch.
+//
+// Watch only sends files that change while it runs, so Push first to bring
+// the theme up to date. It returns nil once ctx is done. callback may be nil.
+func (obj *ThemeSync) Watch(ctx context.Context, callback func(ThemeWatchEvent)) error {
+	if callback == nil {
+		callback = func(ThemeWatchEvent) {}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err = watcher.Add(obj.Dir); err != nil {
+		return err
+	}
+	for _, dir := range THEME_DIRS {
+		if err = obj.watchTree(watcher, filepath.Join(obj.Dir, dir)); err != nil {
+			return err
+		}
+	}
+
+	// sums holds the checksum last sent for each key, so saves that don't
+	// change a file aren't uploaded again.
+	sums, err := obj.local()
+	if err != nil {
+		return err
+	}
+
+	debounce := obj.Debounce
+	if debounce == 0 {
+		debounce = THEME_WATCH_DEBOUNCE
+	}
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	// pending holds each changed key with its file's stamp as of its last
+	// event.
+	pending := map[string]fileStamp{}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err = <-watcher.Errors:
+			callback(ThemeWatchEvent{Err: fmt.Errorf("shopify: watching %s: %w", obj.Dir, err)})
+
+		case event := <-watcher.Events:
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			key, err := obj.key(event.Name)
+			if err != nil {
+				continue
+			}
+
+			if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+				if event.Has(fsnotify.Create) && (themeKey(key) || slices.Contains(THEME_DIRS, key)) {
+					// A directory created or moved in: watch it and send
+					// what it already holds.
+					obj.watchTree(watcher, event.Name)
+					filepath.WalkDir(event.Name, func(file string, d fs.DirEntry, err error) error {
+						if err == nil && !d.IsDir() {
+							if key, err := obj.key(file); err == nil && obj.watched(key) {
+								pending[key] = stamp(file)
+							}
+						}
+						return nil
+					})
+				}
+			} else {
+				if obj.watched(key) {
+					pending[key] = stamp(event.Name)
+				}
+				// A directory removed or moved out takes its files with it.
+				for known := range sums {
+					if strings.HasPrefix(known, key+"/") {
+						pending[known] = stamp(obj.path(known))
+					}
+				}
+			}
+
+			if len(pending) > 0 {
+				timer.Reset(debounce)
+			}
+
+		case <-timer.C:
+			for _, key := range slices.Sorted(maps.Keys(pending)) {
+				if ctx.Err() != nil {
+					return nil
+				}
+				// A file that changed since its last event is still being
+				// written, e.g. truncated but not yet refilled; wait for it
+				// to settle rather than send it half done.
+				if current := stamp(obj.path(key)); current != pending[key] {
+					pending[key] = current
+					continue
+				}
+				if event, ok := obj.send(ctx, key, sums); ok {
+					callback(event)
+				}
+				delete(pending, key)
+			}
+			if len(pending) > 0 {
+				timer.Reset(debounce)
+			}
+		}
+	}
+}
+
+// fileStamp identifies a version of a file by its size and modification
+// time.
+type fileStamp struct {
+	exists  bool
+	size    int64
+	modTime int64
+}
+
+func stamp(file string) fileStamp {
+	info, err := os.Stat(file)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{exists: true, size: info.Size(), modTime: info.ModTime().UnixNano()}
+}
+
+// send uploads the file for key, or deletes its asset if the file is gone.
+// It returns false when there was nothing to do.
+func (obj *ThemeSync) send(ctx context.Context, key string, sums map[string]string) (ThemeWatchEvent, bool) {
+	event := ThemeWatchEvent{Key: key}
+
+	data, err := os.ReadFile(obj.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		if _, ok := sums[key]; !ok {
+			return event, false
+		}
+
+		event.Deleted = true
+		event.Err = obj.api.DeleteCtx(ctx, obj.ThemeId, key)
+		if errors.Is(event.Err, ErrNotFound) {
+			event.Err = nil
+		}
+		if event.Err == nil {
+			delete(sums, key)
+		}
+		return event, true
+	}
+
+	if err != nil {
+		event.Err = err
+		return event, true
+	}
+
+	sum := checksum(data)
+	if sums[key] == sum {
+		return event, false
+	}
+
+	event.Err = obj.upload(ctx, key)
+	if event.Err == nil {
+		sums[key] = sum
+	}
+	return event, true
+}
+
+// watched reports whether Watch sends changes to key: it must be a theme
+// file that isn't ignored, hidden, or an editor's backup.
+func (obj *ThemeSync) watched(key string) bool {
+	if !themeKey(key) || obj.Ignored(key) || strings.HasSuffix(key, "~") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// watchTree watches dir and the directories below it, skipping hidden ones.
+// A missing dir is not an error; Watch picks it up if it is created later.
+func (obj *ThemeSync) watchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && file == dir {
+			return filepath.SkipDir
+		}
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		if file != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(file)
+	})
+}
@@ -0,0 +1,75 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Timestamp is a point in time as sent by Shopify, e.g.
+// "2024-07-01T10:15:00-04:00", keeping the shop's timezone offset.
+//
+// Shopify sends null for things that haven't happened, such as PublishedAt on
+// an unpublished page. Null and "" decode to the zero Timestamp, which
+// encodes back to null and is left out of requests by `omitzero`.
+type Timestamp struct {
+	time.Time
+}
+
+// timestampLayouts are tried in order; some *_on fields are plain dates.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02",
+}
+
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{t}
+}
+
+// ParseTimestamp parses any of the formats Shopify uses for timestamps.
+func ParseTimestamp(s string) (Timestamp, error) {
+	if s == "" {
+		return Timestamp{}, nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Timestamp{t}, nil
+		}
+	}
+	return Timestamp{}, fmt.Errorf("shopify: invalid timestamp %q", s)
+}
+
+func (t Timestamp) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Format(time.RFC3339Nano))
+}
+
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		*t = Timestamp{}
+		return nil
+	}
+
+	str := ""
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	parsed, err := ParseTimestamp(str)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
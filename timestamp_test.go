@@ -0,0 +1,56 @@
+package shopify
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampUnmarshal(t *testing.T) {
+	r := struct {
+		PublishedAt Timestamp `json:"published_at"`
+		CreatedAt   Timestamp `json:"created_at"`
+		UpdatedAt   Timestamp `json:"updated_at"`
+		ExpiresOn   Timestamp `json:"expires_on"`
+	}{}
+	body := `{"published_at": null, "created_at": "2024-07-01T10:15:00-04:00", "updated_at": "", "expires_on": "2025-01-31"}`
+	if err := json.Unmarshal([]byte(body), &r); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !r.PublishedAt.IsZero() || !r.UpdatedAt.IsZero() {
+		t.Errorf("Expected null and empty timestamps to be zero, got %v and %v", r.PublishedAt, r.UpdatedAt)
+	}
+	if _, offset := r.CreatedAt.Zone(); offset != -4*60*60 {
+		t.Errorf("Expected the -04:00 offset to be kept, got %d", offset)
+	}
+	if r.CreatedAt.String() != "2024-07-01T10:15:00-04:00" {
+		t.Errorf("Unexpected created_at %s", r.CreatedAt)
+	}
+	if r.ExpiresOn.Year() != 2025 || r.ExpiresOn.Month() != time.January || r.ExpiresOn.Day() != 31 {
+		t.Errorf("Unexpected expires_on %s", r.ExpiresOn)
+	}
+
+	if err := json.Unmarshal([]byte(`{"created_at": "yesterday"}`), &r); err == nil {
+		t.Errorf("Expected an error for an invalid timestamp")
+	}
+}
+
+func TestTimestampMarshal(t *testing.T) {
+	r := struct {
+		PublishedAt Timestamp `json:"published_at,omitzero"`
+		ClosedAt    Timestamp `json:"closed_at"`
+		CreatedAt   Timestamp `json:"created_at"`
+	}{
+		CreatedAt: NewTimestamp(time.Date(2024, 7, 1, 10, 15, 0, 0, time.FixedZone("", -4*60*60))),
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `{"closed_at":null,"created_at":"2024-07-01T10:15:00-04:00"}`
+	if string(b) != expected {
+		t.Errorf("Expected %s, got %s", expected, b)
+	}
+}
@@ -0,0 +1,179 @@
+package shopify
+
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+)
+
+// Kinds of Transaction.
+const (
+	TransactionAuthorization   = "authorization"
+	TransactionCapture         = "capture"
+	TransactionSale            = "sale"
+	TransactionVoid            = "void"
+	TransactionRefund          = "refund"
+	TransactionSuggestedRefund = "suggested_refund"
+)
+
+type Transaction struct {
+	Amount Money `json:"amount,omitzero"`
+
+	Authorization string `json:"authorization,omitempty"`
+
+	CreatedAt Timestamp `json:"created_at,omitzero"`
+
+	Currency string `json:"currency,omitempty"`
+
+	ErrorCode string `json:"error_code,omitempty"`
+
+	Gateway string `json:"gateway,omitempty"`
+
+	Id int64 `json:"id,omitempty"`
+
+	Kind string `json:"kind,omitempty"`
+
+	MaximumRefundable Money `json:"maximum_refundable,omitzero"`
+
+	Message string `json:"message,omitempty"`
+
+	OrderId int64 `json:"order_id,omitempty"`
+
+	ParentId int64 `json:"parent_id,omitempty"`
+
+	ProcessedAt Timestamp `json:"processed_at,omitzero"`
+
+	SourceName string `json:"source_name,omitempty"`
+
+	Status string `json:"status,omitempty"`
+
+	Test bool `json:"test,omitempty"`
+
+	api *API
+}
+
+func (obj *Order) Transactions() ([]Transaction, error) {
+	return obj.TransactionsCtx(context.Background())
+}
+
+func (obj *Order) TransactionsCtx(ctx context.Context) ([]Transaction, error) {
+	endpoint := fmt.Sprintf("/admin/orders/%d/transactions.json", obj.Id)
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]Transaction{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["transactions"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].api = obj.api
+	}
+
+	return result, nil
+}
+
+func (obj *Order) NewTransaction() *Transaction {
+	return &Transaction{OrderId: obj.Id, api: obj.api}
+}
+
+// Save creates the transaction. Transactions can't be changed once created.
+func (obj *Transaction) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Transaction) SaveCtx(ctx context.Context) error {
+	if obj.Id != 0 {
+		return fmt.Errorf("shopify: transaction %d already exists", obj.Id)
+	}
+
+	endpoint := fmt.Sprintf("/admin/orders/%d/transactions.json", obj.OrderId)
+
+	body := map[string]*Transaction{}
+	body["transaction"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	res, status, err := api.requestContext(ctx, endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != 201 {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]Transaction{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	*obj = r["transaction"]
+	obj.api = api
+
+	return nil
+}
+
+// Capture captures amount of an authorization, or all of it when amount is
+// the zero Money, and returns the capture transaction.
+func (obj *Transaction) Capture(amount Money) (*Transaction, error) {
+	return obj.CaptureCtx(context.Background(), amount)
+}
+
+func (obj *Transaction) CaptureCtx(ctx context.Context, amount Money) (*Transaction, error) {
+	return obj.child(ctx, TransactionCapture, amount)
+}
+
+// Void voids an authorization and returns the void transaction.
+func (obj *Transaction) Void() (*Transaction, error) {
+	return obj.VoidCtx(context.Background())
+}
+
+func (obj *Transaction) VoidCtx(ctx context.Context) (*Transaction, error) {
+	return obj.child(ctx, TransactionVoid, Money{})
+}
+
+func (obj *Transaction) child(ctx context.Context, kind string, amount Money) (*Transaction, error) {
+	result := &Transaction{
+		OrderId:  obj.OrderId,
+		ParentId: obj.Id,
+		Kind:     kind,
+		Amount:   amount,
+		api:      obj.api,
+	}
+	if !amount.IsZero() {
+		result.Currency = amount.Currency
+	}
+
+	err := result.SaveCtx(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -1,29 +1,285 @@
 package shopify
 
+import (
+	"bytes"
+
+	"context"
+
+	"encoding/json"
+
+	"fmt"
+)
+
+// Variant.InventoryQuantity, RequiresShipping and Taxable are pointers so
+// that 0 and false are sent when set, and left alone when nil. Saving a
+// loaded variant only sends InventoryQuantity if it was changed, together
+// with the loaded quantity as OldInventoryQuantity, so Shopify rejects the
+// update if the stock moved in the meantime.
 type Variant struct {
 	Barcode              string      `json:"barcode,omitempty"`
-	CompareAtPrice       string      `json:"compare_at_price,omitempty"`
-	CreatedAt            string      `json:"created_at,omitempty"`
+	CompareAtPrice       Money       `json:"compare_at_price,omitzero"`
+	CreatedAt            Timestamp   `json:"created_at,omitzero"`
 	FulfillmentService   string      `json:"fulfillment_service,omitempty"`
 	Grams                float64     `json:"grams,omitempty"`
 	Weight               float64     `json:"weight,omitempty"`
 	WeightUnit           string      `json:"weight_unit,omitempty"`
 	Id                   int64       `json:"id,omitempty"`
+	InventoryItemId      int64       `json:"inventory_item_id,omitempty"`
 	InventoryManagement  string      `json:"inventory_management,omitempty"`
 	InventoryPolicy      string      `json:"inventory_policy,omitempty"`
-	InventoryQuantity    int64       `json:"inventory_quantity,omitempty"`
+	InventoryQuantity    *int64      `json:"inventory_quantity,omitempty"`
 	OldInventoryQuantity int64       `json:"old_inventory_quantity,omitempty"`
 	Metafield            interface{} `json:"metafield,omitempty"`
 	Option1              string      `json:"option1,omitempty"`
 	Option2              string      `json:"option2,omitempty"`
 	Option3              string      `json:"option3,omitempty"`
 	Position             int64       `json:"position,omitempty"`
-	Price                string      `json:"price,omitempty"`
+	Price                Money       `json:"price,omitzero"`
 	ProductId            int64       `json:"product_id,omitempty"`
-	RequiresShipping     bool        `json:"requires_shipping,omitempty"`
+	RequiresShipping     *bool       `json:"requires_shipping,omitempty"`
 	Sku                  string      `json:"sku,omitempty"`
-	Taxable              bool        `json:"taxable,omitempty"`
+	Taxable              *bool       `json:"taxable,omitempty"`
 	Title                string      `json:"title,omitempty"`
-	UpdatedAt            string      `json:"updated_at,omitempty"`
+	UpdatedAt            Timestamp   `json:"updated_at,omitzero"`
 	ImageId              int64       `json:"image_id,omitempty"`
+
+	// loadedInventory is InventoryQuantity as it was loaded.
+	loadedInventory *int64
+
+	api *API
+}
+
+// bind attaches api to the variant and remembers its loaded stock, so Save
+// can tell whether it was changed.
+func (obj *Variant) bind(api *API) {
+	obj.api = api
+	obj.loadedInventory = nil
+	if obj.InventoryQuantity != nil {
+		quantity := *obj.InventoryQuantity
+		obj.loadedInventory = &quantity
+	}
+}
+
+type VariantsOptions struct {
+	Limit   int    `url:"limit,omitempty"`
+	Page    int    `url:"page,omitempty"`
+	SinceID int64  `url:"since_id,omitempty"`
+	Fields  string `url:"fields,omitempty"`
+}
+
+func (api *API) Variant(id int64) (*Variant, error) {
+	return api.VariantCtx(context.Background(), id)
+}
+
+func (api *API) VariantCtx(ctx context.Context, id int64) (*Variant, error) {
+	endpoint := fmt.Sprintf("/admin/variants/%d.json", id)
+
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := map[string]Variant{}
+	err = json.NewDecoder(res).Decode(&r)
+	result := r["variant"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.bind(api)
+
+	return &result, nil
+}
+
+// ListVariants fetches the product's variants. The Variants field holds the
+// ones embedded in the product when it was loaded.
+func (obj *Product) ListVariants(options *VariantsOptions) ([]Variant, error) {
+	return obj.ListVariantsCtx(context.Background(), options)
+}
+
+func (obj *Product) ListVariantsCtx(ctx context.Context, options *VariantsOptions) ([]Variant, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("/admin/products/%d/variants.json?%v", obj.ID, qs)
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, newResponseError(status, res)
+	}
+
+	r := &map[string][]Variant{}
+	err = json.NewDecoder(res).Decode(r)
+
+	result := (*r)["variants"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].bind(obj.api)
+	}
+
+	return result, nil
+}
+
+func (obj *Product) VariantsCount() (int, error) {
+	return obj.VariantsCountCtx(context.Background())
+}
+
+func (obj *Product) VariantsCountCtx(ctx context.Context) (int, error) {
+	endpoint := fmt.Sprintf("/admin/products/%d/variants/count.json", obj.ID)
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status != 200 {
+		return 0, newResponseError(status, res)
+	}
+
+	r := map[string]int{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return r["count"], nil
+}
+
+func (obj *Product) NewVariant() *Variant {
+	return &Variant{ProductId: obj.ID, api: obj.api}
+}
+
+// Save creates the variant or updates only this variant, leaving the rest of
+// its product untouched.
+func (obj *Variant) Save() error {
+	return obj.SaveCtx(context.Background())
+}
+
+func (obj *Variant) SaveCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/variants/%d.json", obj.Id)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.Id == 0 {
+		endpoint = fmt.Sprintf("/admin/products/%d/variants.json", obj.ProductId)
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	variant := *obj
+	if obj.Id != 0 && obj.loadedInventory != nil {
+		if obj.InventoryQuantity == nil || *obj.InventoryQuantity == *obj.loadedInventory {
+			variant.InventoryQuantity = nil
+			variant.OldInventoryQuantity = 0
+		} else {
+			variant.OldInventoryQuantity = *obj.loadedInventory
+		}
+	}
+
+	body := map[string]*Variant{}
+	body["variant"] = &variant
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	r := map[string]Variant{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["variant"]
+	obj.bind(api)
+
+	return nil
+}
+
+func (obj *Variant) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *Variant) DeleteCtx(ctx context.Context) error {
+	endpoint := fmt.Sprintf("/admin/products/%d/variants/%d.json", obj.ProductId, obj.Id)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		return newResponseError(status, res)
+	}
+
+	return nil
+}
+
+// FindVariantsBySKU returns every variant whose SKU is one of skus. SKUs
+// aren't unique in Shopify, so a SKU may match several variants. The catalog
+// is scanned a page of 250 products at a time, fetching only their variants.
+func (api *API) FindVariantsBySKU(ctx context.Context, skus ...string) ([]Variant, error) {
+	return api.findVariants(ctx, func(v *Variant) string { return v.Sku }, skus)
+}
+
+// FindVariantsByBarcode is FindVariantsBySKU for barcodes such as UPCs.
+func (api *API) FindVariantsByBarcode(ctx context.Context, barcodes ...string) ([]Variant, error) {
+	return api.findVariants(ctx, func(v *Variant) string { return v.Barcode }, barcodes)
+}
+
+func (api *API) findVariants(ctx context.Context, key func(*Variant) string, values []string) ([]Variant, error) {
+	wanted := map[string]bool{}
+	for _, v := range values {
+		if v != "" {
+			wanted[v] = true
+		}
+	}
+
+	result := []Variant{}
+	if len(wanted) == 0 {
+		return result, nil
+	}
+
+	it := api.ProductsIter(ctx, &ProductsOptions{Limit: 250, Fields: "id,variants"})
+	for it.Next() {
+		product := it.Value()
+		for _, v := range product.Variants {
+			if wanted[key(&v)] {
+				result = append(result, v)
+			}
+		}
+	}
+
+	return result, it.Err()
 }
@@ -0,0 +1,51 @@
+package shopify
+
+import (
+	"net/http"
+	"strings"
+)
+
+// VersionNotice reports a response whose Admin API version differs from the
+// one requested, or which Shopify flagged as using deprecated behaviour.
+type VersionNotice struct {
+	Method   string
+	Endpoint string
+
+	RequestedVersion string // API.APIVersion
+	Version          string // X-Shopify-API-Version, the version that served the call
+
+	// DeprecatedReason is X-Shopify-API-Deprecated-Reason, empty unless the
+	// call relies on something scheduled for removal.
+	DeprecatedReason string
+}
+
+// versioned rewrites a legacy /admin/... endpoint to /admin/api/{version}/...
+// when the API is pinned to a version.
+func (api *API) versioned(endpoint string) string {
+	if api.APIVersion == "" || !strings.HasPrefix(endpoint, "/admin/") {
+		return endpoint
+	}
+	if strings.HasPrefix(endpoint, "/admin/api/") || strings.HasPrefix(endpoint, "/admin/oauth/") {
+		return endpoint
+	}
+	return "/admin/api/" + api.APIVersion + strings.TrimPrefix(endpoint, "/admin")
+}
+
+func (api *API) checkVersion(method, endpoint string, header http.Header) {
+	if api.OnVersionNotice == nil {
+		return
+	}
+
+	notice := VersionNotice{
+		Method:           method,
+		Endpoint:         endpoint,
+		RequestedVersion: api.APIVersion,
+		Version:          header.Get("X-Shopify-API-Version"),
+		DeprecatedReason: header.Get("X-Shopify-API-Deprecated-Reason"),
+	}
+
+	mismatch := notice.RequestedVersion != "" && notice.Version != "" && notice.Version != notice.RequestedVersion
+	if mismatch || notice.DeprecatedReason != "" {
+		api.OnVersionNotice(notice)
+	}
+}
@@ -2,32 +2,36 @@ package shopify
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 )
 
 type Webhook struct {
 	Address             string        `json:"address,omitempty"`
-	CreatedAt           time.Time     `json:"created_at,omitempty"`
+	CreatedAt           Timestamp     `json:"created_at,omitzero"`
 	Fields              []interface{} `json:"fields,omitempty"`
 	Format              string        `json:"format,omitempty"`
 	Id                  int64         `json:"id,omitempty"`
 	MetafieldNamespaces []interface{} `json:"metafield_namespaces,omitempty"`
 	Topic               string        `json:"topic,omitempty"`
-	UpdatedAt           time.Time     `json:"updated_at,omitempty"`
+	UpdatedAt           Timestamp     `json:"updated_at,omitzero"`
 	api                 *API
 }
 
 func (api *API) Webhooks() ([]*Webhook, error) {
-	res, status, err := api.request("/admin/webhooks.json", "GET", nil, nil)
+	return api.WebhooksCtx(context.Background())
+}
+
+func (api *API) WebhooksCtx(ctx context.Context) ([]*Webhook, error) {
+	res, status, err := api.requestContext(ctx, "/admin/webhooks.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := &map[string][]*Webhook{}
@@ -46,17 +50,28 @@ func (api *API) Webhooks() ([]*Webhook, error) {
 	return result, nil
 }
 
+// WebhooksIter walks every page of webhooks.
+func (api *API) WebhooksIter(ctx context.Context, options *ListOptions) *Iterator[Webhook] {
+	return newIterator(ctx, api, "/admin/webhooks.json", "webhooks", options,
+		func(v *Webhook) int64 { return v.Id },
+		func(v *Webhook) { v.api = api })
+}
+
 func (api *API) Webhook(id int64) (*Webhook, error) {
+	return api.WebhookCtx(context.Background(), id)
+}
+
+func (api *API) WebhookCtx(ctx context.Context, id int64) (*Webhook, error) {
 	endpoint := fmt.Sprintf("/admin/webhooks/%d.json", id)
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	res, status, err := api.requestContext(ctx, endpoint, "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, newResponseError(status, res)
 	}
 
 	r := map[string]Webhook{}
@@ -78,6 +93,10 @@ func (api *API) NewWebhook() *Webhook {
 }
 
 func (obj *Webhook) Save(partial *Webhook) error {
+	return obj.SaveCtx(context.Background(), partial)
+}
+
+func (obj *Webhook) SaveCtx(ctx context.Context, partial *Webhook) error {
 	endpoint := fmt.Sprintf("/admin/webhooks/%d.json", obj.Id)
 	method := "PUT"
 	expectedStatus := 200
@@ -102,20 +121,14 @@ func (obj *Webhook) Save(partial *Webhook) error {
 		return err
 	}
 
-	res, status, err := obj.api.request(endpoint, method, nil, buf)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, buf)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	r := map[string]Webhook{}
@@ -133,24 +146,22 @@ func (obj *Webhook) Save(partial *Webhook) error {
 }
 
 func (obj *Webhook) Delete() error {
+	return obj.DeleteCtx(context.Background())
+}
+
+func (obj *Webhook) DeleteCtx(ctx context.Context) error {
 	endpoint := fmt.Sprintf("/admin/webhooks/%d.json", obj.Id)
 	method := "DELETE"
 	expectedStatus := 200
 
-	res, status, err := obj.api.request(endpoint, method, nil, nil)
+	res, status, err := obj.api.requestContext(ctx, endpoint, method, nil, nil)
 
 	if err != nil {
 		return err
 	}
 
 	if status != expectedStatus {
-		r := errorResponse{}
-		err = json.NewDecoder(res).Decode(&r)
-		if err == nil {
-			return fmt.Errorf("Status %d: %v", status, r.Errors)
-		} else {
-			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
-		}
+		return newResponseError(status, res)
 	}
 
 	return nil